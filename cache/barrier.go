@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultBarrierTTL          = 24 * time.Hour
+	defaultBarrierPollInterval = 50 * time.Millisecond
+)
+
+// ErrBarrierNotFound 屏障未初始化或已过期
+var ErrBarrierNotFound = errors.New("cache: barrier not found")
+
+// ErrBarrierTimeout 等待屏障归零超时
+var ErrBarrierTimeout = errors.New("cache: barrier wait timeout")
+
+// Barrier 基于 Redis 计数器的跨进程屏障，语义类似 sync.WaitGroup：
+// InitBarrier 设置参与者数量，每个参与者 Arrive 一次，Wait 阻塞直至计数归零
+type Barrier struct {
+	client       *RedisClient
+	ttl          time.Duration
+	pollInterval time.Duration
+}
+
+// NewBarrier 创建屏障实例，ttl 为计数器的过期时间，防止异常退出后遗留 key
+func NewBarrier(client *RedisClient, ttl time.Duration) *Barrier {
+	if ttl <= 0 {
+		ttl = defaultBarrierTTL
+	}
+	return &Barrier{
+		client:       client,
+		ttl:          ttl,
+		pollInterval: defaultBarrierPollInterval,
+	}
+}
+
+// InitBarrier 原子地将屏障计数初始化为 n，已存在的屏障会被覆盖
+func (barrier *Barrier) InitBarrier(ctx context.Context, name string, n int64) error {
+	if n <= 0 {
+		return fmt.Errorf("cache: init barrier %q: count must be positive", barrier.client.maskKey(name))
+	}
+	if err := barrier.client.client.Set(ctx, name, n, barrier.ttl).Err(); err != nil {
+		return barrier.client.errorf("cache: init barrier %q: %w", barrier.client.maskKey(name), err)
+	}
+	return nil
+}
+
+// Arrive 参与者到达屏障，计数减一并返回剩余数量，计数已归零时不再递减
+func (barrier *Barrier) Arrive(ctx context.Context, name string) (int64, error) {
+	luaScript := `
+		local current = redis.call("GET", KEYS[1])
+		if not current then
+			return -1
+		end
+		if tonumber(current) <= 0 then
+			return 0
+		end
+		return redis.call("DECR", KEYS[1])
+	`
+	result, err := barrier.client.client.Eval(ctx, luaScript, []string{name}).Result()
+	if err != nil {
+		return 0, barrier.client.errorf("cache: arrive barrier %q: %w", barrier.client.maskKey(name), err)
+	}
+	remaining := result.(int64)
+	if remaining < 0 {
+		return 0, fmt.Errorf("cache: arrive barrier %q: %w", barrier.client.maskKey(name), ErrBarrierNotFound)
+	}
+	return remaining, nil
+}
+
+// Wait 轮询等待屏障计数归零，超时返回 ErrBarrierTimeout
+func (barrier *Barrier) Wait(ctx context.Context, name string, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(barrier.pollInterval)
+	defer ticker.Stop()
+	for {
+		val, err := barrier.client.client.Get(waitCtx, name).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			return fmt.Errorf("cache: wait barrier %q: %w", barrier.client.maskKey(name), ErrBarrierNotFound)
+		case err == nil:
+			remaining, parseErr := strconv.ParseInt(val, 10, 64)
+			if parseErr != nil {
+				return fmt.Errorf("cache: wait barrier %q: %w", barrier.client.maskKey(name), parseErr)
+			}
+			if remaining <= 0 {
+				return nil
+			}
+		case waitCtx.Err() == nil:
+			return barrier.client.errorf("cache: wait barrier %q: %w", barrier.client.maskKey(name), err)
+		}
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("cache: wait barrier %q: %w", barrier.client.maskKey(name), ctx.Err())
+			}
+			return fmt.Errorf("cache: wait barrier %q: %w", barrier.client.maskKey(name), ErrBarrierTimeout)
+		case <-ticker.C:
+		}
+	}
+}
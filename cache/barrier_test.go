@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBarrierWaitAllArrive 验证等待者仅在所有参与者到达后才解除阻塞
+func TestBarrierWaitAllArrive(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	barrier := NewBarrier(NewRedisClient(client), time.Minute)
+	ctx := context.Background()
+	name := "test_barrier_all_arrive"
+	defer func() {
+		_ = client.Del(ctx, name).Err()
+	}()
+
+	assert.Nil(t, barrier.InitBarrier(ctx, name, 3))
+
+	waitDone := make(chan error, 1)
+	go func() {
+		waitDone <- barrier.Wait(ctx, name, 5*time.Second)
+	}()
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-release
+			_, err := barrier.Arrive(ctx, name)
+			assert.Nil(t, err)
+		}()
+	}
+
+	select {
+	case <-waitDone:
+		t.Fatal("Wait should block until all participants arrive")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	wg.Wait()
+
+	select {
+	case err := <-waitDone:
+		assert.Nil(t, err, "Wait should return after all participants arrive")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait did not unblock after all participants arrived")
+	}
+
+	remaining, err := barrier.Arrive(ctx, name)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), remaining, "Counter should not go below zero")
+}
+
+// TestBarrierWaitTimeout 验证计数未归零时等待超时
+func TestBarrierWaitTimeout(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	barrier := NewBarrier(NewRedisClient(client), time.Minute)
+	ctx := context.Background()
+	name := "test_barrier_timeout"
+	defer func() {
+		_ = client.Del(ctx, name).Err()
+	}()
+
+	assert.Nil(t, barrier.InitBarrier(ctx, name, 2))
+	remaining, err := barrier.Arrive(ctx, name)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), remaining)
+
+	assert.ErrorIs(t, barrier.Wait(ctx, name, 150*time.Millisecond), ErrBarrierTimeout)
+
+	_, err = barrier.Arrive(ctx, "test_barrier_missing")
+	assert.ErrorIs(t, err, ErrBarrierNotFound)
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereal3x/apc/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+// 开启 WithBizErrors 后 Redis 命令错误携带的业务错误码
+const (
+	// CodeCacheMiss 作为错误返回的 redis.Nil（如 ZScore 成员不存在）；Get 等方法内部处理的缺失不返回错误
+	CodeCacheMiss = errs.ERR_CODE_CACHE_MISS
+	// CodeCacheFailure 连接失败、超时、命令错误等真实故障，标记为可重试
+	CodeCacheFailure = errs.ERR_CODE_REDIS_REQUEST
+)
+
+// WithBizErrors 将 RedisClient 方法返回的 Redis 命令错误包装为 errs.BizError（redis.Nil 为 CodeCacheMiss，其余为 CodeCacheFailure），
+// 错误消息保持 "cache: <op> ..." 不变，原始错误保留在错误链中，errors.Is(err, redis.Nil) 等判断不受影响。
+// 作用范围：RedisClient 的方法及基于它构造的组件（WindowCounter、ReliableQueue、Barrier、RWLock、Idempotency、Sequence、Transaction 等）中的 Redis 命令错误；
+// 参数校验、编解码、context 取消以及 ErrQueueEmpty、ErrBarrierNotFound 等组件哨兵错误保持原样；
+// RedisLock 直接基于 *redis.Client 构造，不经过 RedisClient，其错误同样不做转换
+func WithBizErrors() RedisClientOption {
+	return func(r *RedisClient) {
+		r.bizErrors = true
+	}
+}
+
+// errorf 格式化 Redis 命令错误，开启 WithBizErrors 时包装为 BizError
+func (r *RedisClient) errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if !r.bizErrors {
+		return err
+	}
+	if errors.Is(err, redis.Nil) {
+		return errs.CacheMiss(err.Error(), err)
+	}
+	return errs.CacheFailure(err.Error(), err)
+}
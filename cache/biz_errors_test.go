@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereal3x/apc/errs"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithBizErrorsConnectionFailure 验证连接失败返回携带 CodeCacheFailure 的可重试 BizError，原始错误消息保留
+func TestWithBizErrorsConnectionFailure(t *testing.T) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	addr := server.Addr()
+	server.Close()
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1, DialTimeout: 200 * time.Millisecond})
+	defer client.Close()
+	redisClient := NewRedisClient(client, WithBizErrors())
+	ctx := context.Background()
+
+	_, err = redisClient.Get(ctx, "test_biz_error_key")
+	bizErr, ok := errs.AsBizError(err)
+	assert.True(t, ok, "connection failure should be a BizError, got %T", err)
+	assert.Equal(t, CodeCacheFailure, bizErr.Code)
+	assert.True(t, errs.IsRetryable(err))
+	assert.Contains(t, err.Error(), `cache: get "test_biz_error_key"`)
+
+	err = redisClient.SetMany(ctx, map[string]Item{"test_biz_error_key": {Value: "v"}})
+	bizErr, ok = errs.AsBizError(err)
+	assert.True(t, ok, "pipeline failure should be a BizError, got %T", err)
+	assert.Equal(t, CodeCacheFailure, bizErr.Code)
+
+	// 基于 RedisClient 的组件返回同样的错误类型
+	_, windowErr := NewWindowCounter(redisClient).Incr(ctx, "test_biz_error_window", time.Second)
+	queueErr := NewReliableQueue(redisClient, "test_biz_error_queue", "consumer").Push(ctx, "v")
+	barrierErr := NewBarrier(redisClient, time.Minute).InitBarrier(ctx, "test_biz_error_barrier", 1)
+	_, rwlockErr := NewRWLock(redisClient, "test_biz_error_rwlock", time.Second).TryRLock(ctx)
+	_, _, _, idempotencyErr := NewIdempotency(redisClient).Begin(ctx, "test_biz_error_idempotency", time.Minute)
+	for _, componentErr := range []error{windowErr, queueErr, barrierErr, rwlockErr, idempotencyErr} {
+		bizErr, ok = errs.AsBizError(componentErr)
+		assert.True(t, ok, "component failure should be a BizError, got %v", componentErr)
+		assert.Equal(t, CodeCacheFailure, bizErr.Code)
+	}
+}
+
+// TestWithBizErrorsMiss 验证内部处理的缺失不返回错误，作为错误返回的 redis.Nil 携带 CodeCacheMiss 且仍可用 errors.Is 判断
+func TestWithBizErrorsMiss(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	WithBizErrors()(redisClient)
+	ctx := context.Background()
+
+	value, err := redisClient.Get(ctx, "test_biz_error_missing")
+	assert.Nil(t, err)
+	assert.Equal(t, "", value)
+
+	_, err = redisClient.ZScore(ctx, "test_biz_error_zset", "missing")
+	assert.True(t, errors.Is(err, redis.Nil))
+	assert.True(t, errors.Is(err, errs.ErrCacheMiss))
+	bizErr, ok := errs.AsBizError(err)
+	assert.True(t, ok)
+	assert.Equal(t, CodeCacheMiss, bizErr.Code)
+	assert.False(t, errs.IsRetryable(err))
+}
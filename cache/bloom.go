@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBloomNotLoaded 服务端未加载 RedisBloom 模块
+var ErrBloomNotLoaded = errors.New("cache: RedisBloom module not loaded")
+
+// BFReserve 创建指定误判率和容量的布隆过滤器，key 已存在时返回错误
+func (r *RedisClient) BFReserve(ctx context.Context, key string, errorRate float64, capacity int64) error {
+	if err := r.client.BFReserve(ctx, key, errorRate, capacity).Err(); err != nil {
+		return r.errorf("cache: bf.reserve %q: %w", r.maskKey(key), bloomError(err))
+	}
+	return nil
+}
+
+// BFAdd 向布隆过滤器添加元素，返回元素是否为新增（可能因误判返回 false），过滤器不存在时按默认参数自动创建
+func (r *RedisClient) BFAdd(ctx context.Context, key, item string) (bool, error) {
+	added, err := r.client.BFAdd(ctx, key, item).Result()
+	if err != nil {
+		return false, r.errorf("cache: bf.add %q: %w", r.maskKey(key), bloomError(err))
+	}
+	return added, nil
+}
+
+// BFExists 检查元素是否可能存在，false 表示一定不存在，true 存在按误判率计算的误判可能
+func (r *RedisClient) BFExists(ctx context.Context, key, item string) (bool, error) {
+	exists, err := r.client.BFExists(ctx, key, item).Result()
+	if err != nil {
+		return false, r.errorf("cache: bf.exists %q: %w", r.maskKey(key), bloomError(err))
+	}
+	return exists, nil
+}
+
+// bloomError 将未知命令错误转换为 ErrBloomNotLoaded
+func bloomError(err error) error {
+	if isUnknownCommand(err) {
+		return ErrBloomNotLoaded
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedisClientBloomFilter 验证布隆过滤器添加和检查元素，未加载 RedisBloom 模块时跳过
+func TestRedisClientBloomFilter(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	key := "test_bloom_user_ids"
+	defer func() {
+		_ = client.Del(ctx, key).Err()
+	}()
+	_ = client.Del(ctx, key).Err()
+
+	err := redisClient.BFReserve(ctx, key, 0.001, 10000)
+	if errors.Is(err, ErrBloomNotLoaded) {
+		t.Skip("RedisBloom module not loaded")
+	}
+	assert.Nil(t, err)
+
+	added, err := redisClient.BFAdd(ctx, key, "user:1")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = redisClient.BFAdd(ctx, key, "user:1")
+	assert.Nil(t, err)
+	assert.False(t, added, "duplicate item should not be reported as added")
+
+	exists, err := redisClient.BFExists(ctx, key, "user:1")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = redisClient.BFExists(ctx, key, "user:2")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+// TestRedisClientBloomNotLoaded 验证未加载模块时返回 ErrBloomNotLoaded
+func TestRedisClientBloomNotLoaded(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+
+	_, err = redisClient.BFAdd(context.Background(), "test_bloom", "item")
+	assert.ErrorIs(t, err, ErrBloomNotLoaded)
+}
@@ -2,23 +2,148 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/metric"
 )
 
+const (
+	defaultDelBatchSize = 500
+	defaultScanCount    = 1000
+	defaultSAddBatch    = 1000
+	// saddPipelineDepth SAddBatched 单次管道最多携带的 SADD 批次数，限制客户端缓冲与单次往返耗时
+	saddPipelineDepth = 16
+)
+
+// ErrFlushNotConfirmed 未显式确认时拒绝清空数据库
+var ErrFlushNotConfirmed = errors.New("cache: flushdb requires confirmation")
+
+// ExpireFlag EXPIRE 命令的条件选项
+type ExpireFlag int
+
+const (
+	// ExpireNX 仅当 key 没有过期时间时设置
+	ExpireNX ExpireFlag = iota + 1
+	// ExpireXX 仅当 key 已有过期时间时设置
+	ExpireXX
+	// ExpireGT 仅当新过期时间大于当前值时设置
+	ExpireGT
+	// ExpireLT 仅当新过期时间小于当前值时设置
+	ExpireLT
+)
+
+// String 返回选项对应的命令参数
+func (flag ExpireFlag) String() string {
+	switch flag {
+	case ExpireNX:
+		return "NX"
+	case ExpireXX:
+		return "XX"
+	case ExpireGT:
+		return "GT"
+	case ExpireLT:
+		return "LT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Result 描述批量读取时单个 key 的结果，Found 为 false 表示 key 不存在，可区分缺失与空字符串
+type Result struct {
+	Key   string
+	Value string
+	Found bool
+}
+
+// Item 描述批量写入时单个 key 的值与过期时间
+type Item struct {
+	Value any
+	TTL   time.Duration
+}
+
 type RedisClient struct {
 	client *redis.Client
+	clock  Clock
+	codec  Codec
+
+	txMaxRetries int
+	poolMetrics  metric.Registration
+	keyMasker    func(key string) string
+	bizErrors    bool
+	ttlJitter    time.Duration
+
+	staleRefreshing sync.Map
 }
 
-func NewRedisClient(client *redis.Client) *RedisClient {
-	return &RedisClient{client: client}
+// RedisClientOption RedisClient 配置选项
+type RedisClientOption func(*RedisClient)
+
+func NewRedisClient(client *redis.Client, opts ...RedisClientOption) *RedisClient {
+	redisClient := &RedisClient{
+		client:       client,
+		clock:        realClock{},
+		codec:        jsonCodec{},
+		txMaxRetries: defaultTxMaxRetries,
+	}
+	for _, option := range opts {
+		option(redisClient)
+	}
+	return redisClient
+}
+
+// scopedClient 派生共享连接池、但选项与钩子相互独立的客户端副本，供只作用于当前 RedisClient 的选项修改，
+// 不影响共用底层客户端的其他组件
+func (r *RedisClient) scopedClient() *redis.Client {
+	opt := r.client.Options()
+	clone := r.client.WithTimeout(opt.ReadTimeout)
+	clone.Options().WriteTimeout = opt.WriteTimeout
+	return clone
+}
+
+// NewRedisClientFromOptions 根据配置创建 go-redis 客户端，创建后通过 ctx 执行 PING 校验连通性，失败时关闭客户端并返回错误
+func NewRedisClientFromOptions(ctx context.Context, opts *redis.Options, clientOpts ...RedisClientOption) (*RedisClient, error) {
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("cache: ping %s: %w", opts.Addr, err)
+	}
+	return NewRedisClient(client, clientOpts...), nil
+}
+
+// NewSecureClient 创建启用 TLS 与 ACL 用户名/密码认证的客户端，PING 校验连通性失败时关闭连接并返回错误
+// tlsConfig 为 nil 时不启用 TLS，username 为空时使用 requirepass 方式的密码认证
+func NewSecureClient(ctx context.Context, addr, username, password string, tlsConfig *tls.Config, clientOpts ...RedisClientOption) (*RedisClient, error) {
+	return NewRedisClientFromOptions(ctx, &redis.Options{
+		Addr:      addr,
+		Username:  username,
+		Password:  password,
+		TLSConfig: tlsConfig,
+	}, clientOpts...)
 }
 
-// Close 关闭 Redis 连接
+// Ping 检查与 Redis 的连通性，可作为健康检查函数注册
+func (r *RedisClient) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return r.errorf("cache: ping: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭底层 Redis 连接池，通过 NewRedisClient 注入的客户端同样会被关闭，与其他组件共用时应由调用方统一关闭
+// 开启 WithPoolMetrics 时同时注销指标采集
 func (r *RedisClient) Close() error {
+	if r.poolMetrics != nil {
+		_ = r.poolMetrics.Unregister()
+		r.poolMetrics = nil
+	}
 	return r.client.Close()
 }
 
@@ -29,30 +154,101 @@ func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
 		return "", nil // 业务层自己判断空值
 	}
 	if err != nil {
-		return "", fmt.Errorf("cache: get %q: %w", key, err)
+		return "", r.errorf("cache: get %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
 
-// Set 设置单个key的值
+// GetExists 获取单个key的值，found 仅在 key 不存在时为 false，可区分缺失与空字符串
+func (r *RedisClient) GetExists(ctx context.Context, key string) (string, bool, error) {
+	val, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, r.errorf("cache: get %q: %w", r.maskKey(key), err)
+	}
+	return val, true, nil
+}
+
+// GetAny 获取 key 并自动识别 JSON，合法 JSON 返回解码后的结构（map[string]any、[]any、float64、bool 等），
+// 否则返回原始字符串；found 为 false 表示 key 不存在
+// 注意纯数字或 true/false 等字符串同样是合法 JSON，会被解码为对应类型
+func (r *RedisClient) GetAny(ctx context.Context, key string) (any, bool, error) {
+	val, found, err := r.GetExists(ctx, key)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(val), &decoded); err != nil {
+		return val, true, nil
+	}
+	return decoded, true, nil
+}
+
+// Set 设置单个key的值，ttl 不是整秒时使用 PX 写入以保留毫秒精度，配置 WithTTLJitter 时追加随机抖动
 func (r *RedisClient) Set(ctx context.Context, key string, val any, ttl time.Duration) error {
-	if err := r.client.Set(ctx, key, val, ttl).Err(); err != nil {
-		return fmt.Errorf("cache: set %q: %w", key, err)
+	if err := r.client.Set(ctx, key, val, r.jitterTTL(ttl)).Err(); err != nil {
+		return r.errorf("cache: set %q: %w", r.maskKey(key), err)
 	}
 	return nil
 }
 
+// SetRange 从字节偏移 offset 开始覆盖写入 value，用于修改定长二进制记录的局部内容而无需整体读改写；
+// 原值长度不足 offset 时以零字节补齐，key 不存在时按空串处理，返回写入后的值长度
+func (r *RedisClient) SetRange(ctx context.Context, key string, offset int64, value string) (int64, error) {
+	length, err := r.client.SetRange(ctx, key, offset, value).Result()
+	if err != nil {
+		return 0, r.errorf("cache: setrange %q: %w", r.maskKey(key), err)
+	}
+	return length, nil
+}
+
+// GetRange 获取值在 [start, end] 字节区间（闭区间）内的子串，负数表示从末尾倒数，key 不存在时返回空串
+func (r *RedisClient) GetRange(ctx context.Context, key string, start, end int64) (string, error) {
+	val, err := r.client.GetRange(ctx, key, start, end).Result()
+	if err != nil {
+		return "", r.errorf("cache: getrange %q: %w", r.maskKey(key), err)
+	}
+	return val, nil
+}
+
 // Del 删除指定的key
 func (r *RedisClient) Del(ctx context.Context, keys ...string) error {
 	if len(keys) == 0 {
 		return nil
 	}
 	if err := r.client.Del(ctx, keys...).Err(); err != nil {
-		return fmt.Errorf("cache: del %v: %w", keys, err)
+		return r.errorf("cache: del %v: %w", r.maskKeys(keys), err)
 	}
 	return nil
 }
 
+// DelBatched 将大量 key 按 batchSize 分组，在单个管道中逐组 UNLINK 并返回实际删除数量
+// UNLINK 在后台线程回收内存，避免单条 DEL 删除海量 key 时阻塞 Redis，batchSize <= 0 时默认 500
+func (r *RedisClient) DelBatched(ctx context.Context, keys []string, batchSize int) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = defaultDelBatchSize
+	}
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.IntCmd, 0, (len(keys)+batchSize-1)/batchSize)
+	for start := 0; start < len(keys); start += batchSize {
+		end := min(start+batchSize, len(keys))
+		cmds = append(cmds, pipe.Unlink(ctx, keys[start:end]...))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, r.errorf("cache: del batched %d keys: %w", len(keys), err)
+	}
+	var removed int64
+	for _, cmd := range cmds {
+		removed += cmd.Val()
+	}
+	return removed, nil
+}
+
 // MGet 批量获取多个key的值
 func (r *RedisClient) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
 	if len(keys) == 0 {
@@ -60,35 +256,143 @@ func (r *RedisClient) MGet(ctx context.Context, keys ...string) ([]interface{},
 	}
 	result, err := r.client.MGet(ctx, keys...).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: mget %v: %w", keys, err)
+		return nil, r.errorf("cache: mget %v: %w", r.maskKeys(keys), err)
 	}
 	return result, nil
 }
 
+// MGetJSON 单次往返批量获取多个 key 并按 JSON 解码为 T，不存在的 key 不出现在结果中
+// 个别 key 解码失败时返回其余成功解码的结果，错误中标明失败的 key
+func MGetJSON[T any](ctx context.Context, r *RedisClient, keys ...string) (map[string]T, error) {
+	values, err := r.MGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]T, len(values))
+	var decodeErrs []error
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var decoded T
+		if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+			decodeErrs = append(decodeErrs, fmt.Errorf("cache: mget json %q: %w", r.maskKey(keys[i]), err))
+			continue
+		}
+		result[keys[i]] = decoded
+	}
+	return result, errors.Join(decodeErrs...)
+}
+
+// MGetResults 批量获取多个key的值，结果与 keys 一一对应，调用方无需对返回值做类型断言
+func (r *RedisClient) MGetResults(ctx context.Context, keys ...string) ([]Result, error) {
+	values, err := r.MGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+		if value, ok := values[i].(string); ok {
+			results[i].Value = value
+			results[i].Found = true
+		}
+	}
+	return results, nil
+}
+
 // MSet 批量设置多个key-value对
 func (r *RedisClient) MSet(ctx context.Context, values ...interface{}) error {
 	if len(values) == 0 || len(values)%2 != 0 {
 		return errors.New("cache: mset requires even number of arguments")
 	}
 	if err := r.client.MSet(ctx, values...).Err(); err != nil {
-		return fmt.Errorf("cache: mset: %w", err)
+		return r.errorf("cache: mset: %w", err)
 	}
 	return nil
 }
 
+// SetMany 通过单次管道批量写入多个 key，每个 key 使用各自的过期时间，配置 WithTTLJitter 时逐个追加随机抖动
+func (r *RedisClient) SetMany(ctx context.Context, items map[string]Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for key, item := range items {
+		pipe.Set(ctx, key, item.Value, r.jitterTTL(item.TTL))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return r.errorf("cache: setmany: %w", err)
+	}
+	return nil
+}
+
+// SetManyNX 通过单次管道批量写入不存在的 key，已存在的 key 保持不变，返回每个 key 是否被写入
+func (r *RedisClient) SetManyNX(ctx context.Context, items map[string]Item) (map[string]bool, error) {
+	if len(items) == 0 {
+		return map[string]bool{}, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make(map[string]*redis.BoolCmd, len(items))
+	for key, item := range items {
+		cmds[key] = pipe.SetNX(ctx, key, item.Value, item.TTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, r.errorf("cache: setmany nx: %w", err)
+	}
+	written := make(map[string]bool, len(cmds))
+	for key, cmd := range cmds {
+		written[key] = cmd.Val()
+	}
+	return written, nil
+}
+
 // Expire 设置key的过期时间
 func (r *RedisClient) Expire(ctx context.Context, key string, ttl time.Duration) error {
 	if err := r.client.Expire(ctx, key, ttl).Err(); err != nil {
-		return fmt.Errorf("cache: expire %q: %w", key, err)
+		return r.errorf("cache: expire %q: %w", r.maskKey(key), err)
 	}
 	return nil
 }
 
+// ExpireWithFlags 按条件设置过期时间，返回过期时间是否被修改，依赖 Redis 7.0+，低版本服务端会返回参数错误
+// GT 对无过期时间的 key 视为无穷大，因此不会为永久 key 设置过期时间
+func (r *RedisClient) ExpireWithFlags(ctx context.Context, key string, ttl time.Duration, flag ExpireFlag) (bool, error) {
+	var cmd *redis.BoolCmd
+	switch flag {
+	case ExpireNX:
+		cmd = r.client.ExpireNX(ctx, key, ttl)
+	case ExpireXX:
+		cmd = r.client.ExpireXX(ctx, key, ttl)
+	case ExpireGT:
+		cmd = r.client.ExpireGT(ctx, key, ttl)
+	case ExpireLT:
+		cmd = r.client.ExpireLT(ctx, key, ttl)
+	default:
+		return false, fmt.Errorf("cache: expire %q: unknown flag %d", r.maskKey(key), flag)
+	}
+	changed, err := cmd.Result()
+	if err != nil {
+		return false, r.errorf("cache: expire %s %q: %w", flag, r.maskKey(key), err)
+	}
+	return changed, nil
+}
+
 // TTL 获取key的剩余过期时间
 func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error) {
 	ttl, err := r.client.TTL(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: ttl %q: %w", key, err)
+		return 0, r.errorf("cache: ttl %q: %w", r.maskKey(key), err)
+	}
+	return ttl, nil
+}
+
+// PTTL 获取key的毫秒精度剩余过期时间，key 不存在时返回 -2ns，未设置过期时返回 -1ns（与 TTL 一致）
+func (r *RedisClient) PTTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, r.errorf("cache: pttl %q: %w", r.maskKey(key), err)
 	}
 	return ttl, nil
 }
@@ -97,11 +401,33 @@ func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error
 func (r *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error) {
 	count, err := r.client.Exists(ctx, keys...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: exists %v: %w", keys, err)
+		return 0, r.errorf("cache: exists %v: %w", r.maskKeys(keys), err)
 	}
 	return count, nil
 }
 
+// ExistsEach 通过管道逐个检查 key 是否存在，返回每个 key 对应的存在状态，重复的 key 只检查一次
+func (r *RedisClient) ExistsEach(ctx context.Context, keys ...string) (map[string]bool, error) {
+	if len(keys) == 0 {
+		return map[string]bool{}, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make(map[string]*redis.IntCmd, len(keys))
+	for _, key := range keys {
+		if _, ok := cmds[key]; !ok {
+			cmds[key] = pipe.Exists(ctx, key)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, r.errorf("cache: exists each %v: %w", r.maskKeys(keys), err)
+	}
+	result := make(map[string]bool, len(cmds))
+	for key, cmd := range cmds {
+		result[key] = cmd.Val() > 0
+	}
+	return result, nil
+}
+
 // HGet 获取哈希表中的字段值
 func (r *RedisClient) HGet(ctx context.Context, key, field string) (string, error) {
 	val, err := r.client.HGet(ctx, key, field).Result()
@@ -109,7 +435,7 @@ func (r *RedisClient) HGet(ctx context.Context, key, field string) (string, erro
 		return "", nil
 	}
 	if err != nil {
-		return "", fmt.Errorf("cache: hget %q:%q: %w", key, field, err)
+		return "", r.errorf("cache: hget %q:%q: %w", r.maskKey(key), field, err)
 	}
 	return val, nil
 }
@@ -120,16 +446,56 @@ func (r *RedisClient) HSet(ctx context.Context, key string, values ...interface{
 		return errors.New("cache: hset requires even number of arguments")
 	}
 	if err := r.client.HSet(ctx, key, values...).Err(); err != nil {
-		return fmt.Errorf("cache: hset %q: %w", key, err)
+		return r.errorf("cache: hset %q: %w", r.maskKey(key), err)
 	}
 	return nil
 }
 
+// HSetNX 仅当字段不存在时设置哈希字段，返回字段是否为新建，可用于检测字段的首次初始化
+func (r *RedisClient) HSetNX(ctx context.Context, key, field string, val interface{}) (bool, error) {
+	created, err := r.client.HSetNX(ctx, key, field, val).Result()
+	if err != nil {
+		return false, r.errorf("cache: hsetnx %q:%q: %w", r.maskKey(key), field, err)
+	}
+	return created, nil
+}
+
+// HRandField 随机返回哈希表中的字段，count 为正数时返回不重复的字段，为负数时允许重复，key 不存在时返回空切片
+func (r *RedisClient) HRandField(ctx context.Context, key string, count int) ([]string, error) {
+	fields, err := r.client.HRandField(ctx, key, count).Result()
+	if err != nil {
+		return nil, r.errorf("cache: hrandfield %q: %w", r.maskKey(key), err)
+	}
+	return fields, nil
+}
+
 // HGetAll 获取哈希表中所有的字段和值
 func (r *RedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
 	result, err := r.client.HGetAll(ctx, key).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: hgetall %q: %w", key, err)
+		return nil, r.errorf("cache: hgetall %q: %w", r.maskKey(key), err)
+	}
+	return result, nil
+}
+
+// HGetAllMany 通过单次管道批量执行 HGETALL，返回以原始 key 为索引的结果，不存在或为空的哈希不出现在结果中
+func (r *RedisClient) HGetAllMany(ctx context.Context, keys ...string) (map[string]map[string]string, error) {
+	if len(keys) == 0 {
+		return map[string]map[string]string{}, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.HGetAll(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, r.errorf("cache: hgetall many %v: %w", r.maskKeys(keys), err)
+	}
+	result := make(map[string]map[string]string, len(keys))
+	for i, cmd := range cmds {
+		if fields := cmd.Val(); len(fields) > 0 {
+			result[keys[i]] = fields
+		}
 	}
 	return result, nil
 }
@@ -138,7 +504,7 @@ func (r *RedisClient) HGetAll(ctx context.Context, key string) (map[string]strin
 func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	val, err := r.client.Incr(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: incr %q: %w", key, err)
+		return 0, r.errorf("cache: incr %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
@@ -147,7 +513,7 @@ func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 func (r *RedisClient) Decr(ctx context.Context, key string) (int64, error) {
 	val, err := r.client.Decr(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: decr %q: %w", key, err)
+		return 0, r.errorf("cache: decr %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
@@ -156,7 +522,7 @@ func (r *RedisClient) Decr(ctx context.Context, key string) (int64, error) {
 func (r *RedisClient) IncrBy(ctx context.Context, key string, step int64) (int64, error) {
 	val, err := r.client.IncrBy(ctx, key, step).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: incrby %q: %w", key, err)
+		return 0, r.errorf("cache: incrby %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
@@ -165,7 +531,18 @@ func (r *RedisClient) IncrBy(ctx context.Context, key string, step int64) (int64
 func (r *RedisClient) DecrBy(ctx context.Context, key string, step int64) (int64, error) {
 	val, err := r.client.DecrBy(ctx, key, step).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: decrby %q: %w", key, err)
+		return 0, r.errorf("cache: decrby %q: %w", r.maskKey(key), err)
+	}
+	return val, nil
+}
+
+// IncrByFloat 对key的值进行浮点步长的自增操作，step 为负数时即自减
+// 注意：Redis 以 long double 计算并以字符串存储结果，多次累加会产生浮点误差（如 0.1 累加十次不严格等于 1），
+// 金额等需要精确结果的场景应使用整数最小单位配合 IncrBy
+func (r *RedisClient) IncrByFloat(ctx context.Context, key string, step float64) (float64, error) {
+	val, err := r.client.IncrByFloat(ctx, key, step).Result()
+	if err != nil {
+		return 0, r.errorf("cache: incrbyfloat %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
@@ -174,34 +551,144 @@ func (r *RedisClient) DecrBy(ctx context.Context, key string, step int64) (int64
 func (r *RedisClient) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
 	count, err := r.client.SAdd(ctx, key, members...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: sadd %q: %w", key, err)
+		return 0, r.errorf("cache: sadd %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
 
+// SAddBatched 将大量成员按 batchSize 分批 SADD 并通过管道发送，返回新增成员总数，batchSize <= 0 时默认 1000
+// 每个管道最多携带 16 批，超大输入不会产生单条巨型命令或一次性缓冲全部请求；中途失败时已发送的批次不会回滚
+func (r *RedisClient) SAddBatched(ctx context.Context, key string, batchSize int, members ...interface{}) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = defaultSAddBatch
+	}
+	var added int64
+	for start := 0; start < len(members); start += batchSize * saddPipelineDepth {
+		pipe := r.client.Pipeline()
+		cmds := make([]*redis.IntCmd, 0, saddPipelineDepth)
+		end := min(start+batchSize*saddPipelineDepth, len(members))
+		for batchStart := start; batchStart < end; batchStart += batchSize {
+			cmds = append(cmds, pipe.SAdd(ctx, key, members[batchStart:min(batchStart+batchSize, end)]...))
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return added, r.errorf("cache: sadd batched %q: %w", r.maskKey(key), err)
+		}
+		for _, cmd := range cmds {
+			added += cmd.Val()
+		}
+	}
+	return added, nil
+}
+
 // SMembers 获取集合中的所有元素
 func (r *RedisClient) SMembers(ctx context.Context, key string) ([]string, error) {
 	members, err := r.client.SMembers(ctx, key).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: smembers %q: %w", key, err)
+		return nil, r.errorf("cache: smembers %q: %w", r.maskKey(key), err)
 	}
 	return members, nil
 }
 
+// SMembersFunc 通过 SSCAN 分页遍历集合并逐个回调 fn，无需一次性持有全部成员，fn 返回错误时停止遍历并返回该错误
+// SSCAN 的语义保证遍历期间一直存在的成员至少返回一次，遍历过程中集合被修改时成员可能重复出现
+func (r *RedisClient) SMembersFunc(ctx context.Context, key string, fn func(member string) error) error {
+	var cursor uint64
+	for {
+		members, nextCursor, err := r.client.SScan(ctx, key, cursor, "", defaultScanCount).Result()
+		if err != nil {
+			return r.errorf("cache: sscan %q: %w", r.maskKey(key), err)
+		}
+		for _, member := range members {
+			if err := fn(member); err != nil {
+				return err
+			}
+		}
+		if nextCursor == 0 {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// SMembersInt 获取集合所有成员并解析为 int64，存在非整数成员时返回错误
+func (r *RedisClient) SMembersInt(ctx context.Context, key string) ([]int64, error) {
+	return SMembersAs(ctx, r, key, func(member string) (int64, error) {
+		return strconv.ParseInt(member, 10, 64)
+	})
+}
+
+// SMembersAs 获取集合所有成员并通过 parse 转换为 T，任一成员解析失败时返回包含该成员的错误
+func SMembersAs[T any](ctx context.Context, r *RedisClient, key string, parse func(string) (T, error)) ([]T, error) {
+	members, err := r.SMembers(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, 0, len(members))
+	for _, member := range members {
+		value, err := parse(member)
+		if err != nil {
+			return nil, r.errorf("cache: smembers %q: parse member %q: %w", r.maskKey(key), member, err)
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// CountMatching 通过 SSCAN MATCH 统计集合中匹配 glob 模式的成员数量，不阻塞服务端；
+// 遍历期间集合被修改时结果为近似值，仅需总数时应使用 SCard
+func (r *RedisClient) CountMatching(ctx context.Context, key, pattern string) (int64, error) {
+	var (
+		cursor uint64
+		count  int64
+	)
+	for {
+		members, nextCursor, err := r.client.SScan(ctx, key, cursor, pattern, defaultScanCount).Result()
+		if err != nil {
+			return 0, r.errorf("cache: sscan %q match %q: %w", r.maskKey(key), pattern, err)
+		}
+		count += int64(len(members))
+		if nextCursor == 0 {
+			return count, nil
+		}
+		cursor = nextCursor
+	}
+}
+
 // SIsMember 检查元素是否在集合中
 func (r *RedisClient) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
 	exists, err := r.client.SIsMember(ctx, key, member).Result()
 	if err != nil {
-		return false, fmt.Errorf("cache: sismember %q: %w", key, err)
+		return false, r.errorf("cache: sismember %q: %w", r.maskKey(key), err)
 	}
 	return exists, nil
 }
 
+// SIsMembersPipelined 通过单次管道批量检查多个元素是否在集合中，结果顺序与 members 一致
+// Redis 6.2+ 支持 SMISMEMBER 时优先使用原生命令（UniversalClient().SMIsMember），本方法用于低版本兼容
+func (r *RedisClient) SIsMembersPipelined(ctx context.Context, key string, members ...interface{}) ([]bool, error) {
+	if len(members) == 0 {
+		return []bool{}, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.BoolCmd, len(members))
+	for i, member := range members {
+		cmds[i] = pipe.SIsMember(ctx, key, member)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, r.errorf("cache: sismember pipelined %q: %w", r.maskKey(key), err)
+	}
+	result := make([]bool, len(cmds))
+	for i, cmd := range cmds {
+		result[i] = cmd.Val()
+	}
+	return result, nil
+}
+
 // SCard 获取集合中元素的数量
 func (r *RedisClient) SCard(ctx context.Context, key string) (int64, error) {
 	count, err := r.client.SCard(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: scard %q: %w", key, err)
+		return 0, r.errorf("cache: scard %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -210,11 +697,36 @@ func (r *RedisClient) SCard(ctx context.Context, key string) (int64, error) {
 func (r *RedisClient) SetNX(ctx context.Context, key string, val any, ttl time.Duration) (bool, error) {
 	result, err := r.client.SetNX(ctx, key, val, ttl).Result()
 	if err != nil {
-		return false, fmt.Errorf("cache: setnx %q: %w", key, err)
+		return false, r.errorf("cache: setnx %q: %w", r.maskKey(key), err)
 	}
 	return result, nil
 }
 
+// CAS 原子比较并交换，当前值等于 expected 时写入 newVal 并返回 true，expected 为空字符串表示要求 key 不存在
+// ttl <= 0 时写入的值不过期
+func (r *RedisClient) CAS(ctx context.Context, key, expected, newVal string, ttl time.Duration) (bool, error) {
+	luaScript := `
+		local current = redis.call("GET", KEYS[1])
+		if current == false then
+			current = ""
+		end
+		if current ~= ARGV[1] then
+			return 0
+		end
+		if tonumber(ARGV[3]) > 0 then
+			redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+		else
+			redis.call("SET", KEYS[1], ARGV[2])
+		end
+		return 1
+	`
+	swapped, err := r.client.Eval(ctx, luaScript, []string{key}, expected, newVal, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, r.errorf("cache: cas %q: %w", r.maskKey(key), err)
+	}
+	return swapped == 1, nil
+}
+
 // SRem 从集合中删除指定成员
 func (r *RedisClient) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
 	if len(members) == 0 {
@@ -222,7 +734,7 @@ func (r *RedisClient) SRem(ctx context.Context, key string, members ...interface
 	}
 	count, err := r.client.SRem(ctx, key, members...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: srem %q: %w", key, err)
+		return 0, r.errorf("cache: srem %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -234,11 +746,40 @@ func (r *RedisClient) SInter(ctx context.Context, keys ...string) ([]string, err
 	}
 	result, err := r.client.SInter(ctx, keys...).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: sinter %v: %w", keys, err)
+		return nil, r.errorf("cache: sinter %v: %w", r.maskKeys(keys), err)
 	}
 	return result, nil
 }
 
+// SInterCard 获取多个集合交集的元素数量，limit > 0 时计数达到 limit 即提前停止，无需返回交集成员
+// 依赖 Redis 7.0+ 的 SINTERCARD 命令，服务端不支持时回退为 SINTER 后取长度，回退路径会完整物化交集，大集合上开销较高
+func (r *RedisClient) SInterCard(ctx context.Context, limit int64, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	count, err := r.client.SInterCard(ctx, limit, keys...).Result()
+	if err == nil {
+		return count, nil
+	}
+	if !isUnknownCommand(err) {
+		return 0, r.errorf("cache: sintercard %v: %w", r.maskKeys(keys), err)
+	}
+	members, err := r.SInter(ctx, keys...)
+	if err != nil {
+		return 0, err
+	}
+	count = int64(len(members))
+	if limit > 0 && count > limit {
+		count = limit
+	}
+	return count, nil
+}
+
+// isUnknownCommand 判断错误是否为服务端不支持该命令
+func isUnknownCommand(err error) bool {
+	return strings.HasPrefix(err.Error(), "ERR unknown command")
+}
+
 // SUnion 获取多个集合的并集
 func (r *RedisClient) SUnion(ctx context.Context, keys ...string) ([]string, error) {
 	if len(keys) == 0 {
@@ -246,7 +787,7 @@ func (r *RedisClient) SUnion(ctx context.Context, keys ...string) ([]string, err
 	}
 	result, err := r.client.SUnion(ctx, keys...).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: sunion %v: %w", keys, err)
+		return nil, r.errorf("cache: sunion %v: %w", r.maskKeys(keys), err)
 	}
 	return result, nil
 }
@@ -258,7 +799,7 @@ func (r *RedisClient) SDiff(ctx context.Context, keys ...string) ([]string, erro
 	}
 	result, err := r.client.SDiff(ctx, keys...).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: sdiff %v: %w", keys, err)
+		return nil, r.errorf("cache: sdiff %v: %w", r.maskKeys(keys), err)
 	}
 	return result, nil
 }
@@ -270,7 +811,7 @@ func (r *RedisClient) HDel(ctx context.Context, key string, fields ...string) (i
 	}
 	count, err := r.client.HDel(ctx, key, fields...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: hdel %q: %w", key, err)
+		return 0, r.errorf("cache: hdel %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -279,7 +820,7 @@ func (r *RedisClient) HDel(ctx context.Context, key string, fields ...string) (i
 func (r *RedisClient) HExists(ctx context.Context, key, field string) (bool, error) {
 	exists, err := r.client.HExists(ctx, key, field).Result()
 	if err != nil {
-		return false, fmt.Errorf("cache: hexists %q:%q: %w", key, field, err)
+		return false, r.errorf("cache: hexists %q:%q: %w", r.maskKey(key), field, err)
 	}
 	return exists, nil
 }
@@ -288,7 +829,7 @@ func (r *RedisClient) HExists(ctx context.Context, key, field string) (bool, err
 func (r *RedisClient) HKeys(ctx context.Context, key string) ([]string, error) {
 	keys, err := r.client.HKeys(ctx, key).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: hkeys %q: %w", key, err)
+		return nil, r.errorf("cache: hkeys %q: %w", r.maskKey(key), err)
 	}
 	return keys, nil
 }
@@ -297,7 +838,7 @@ func (r *RedisClient) HKeys(ctx context.Context, key string) ([]string, error) {
 func (r *RedisClient) HVals(ctx context.Context, key string) ([]string, error) {
 	vals, err := r.client.HVals(ctx, key).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: hvals %q: %w", key, err)
+		return nil, r.errorf("cache: hvals %q: %w", r.maskKey(key), err)
 	}
 	return vals, nil
 }
@@ -306,7 +847,7 @@ func (r *RedisClient) HVals(ctx context.Context, key string) ([]string, error) {
 func (r *RedisClient) HLen(ctx context.Context, key string) (int64, error) {
 	count, err := r.client.HLen(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: hlen %q: %w", key, err)
+		return 0, r.errorf("cache: hlen %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -315,7 +856,7 @@ func (r *RedisClient) HLen(ctx context.Context, key string) (int64, error) {
 func (r *RedisClient) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
 	val, err := r.client.HIncrBy(ctx, key, field, incr).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: hincrby %q:%q: %w", key, field, err)
+		return 0, r.errorf("cache: hincrby %q:%q: %w", r.maskKey(key), field, err)
 	}
 	return val, nil
 }
@@ -327,11 +868,30 @@ func (r *RedisClient) ZAdd(ctx context.Context, key string, members ...redis.Z)
 	}
 	count, err := r.client.ZAdd(ctx, key, members...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: zadd %q: %w", key, err)
+		return 0, r.errorf("cache: zadd %q: %w", r.maskKey(key), err)
+	}
+	return count, nil
+}
+
+// ZAddArgs 按 ZADD 标志写入有序集合成员：gt/lt 仅在新分数更大/更小时更新已有成员，nx 仅新增不更新，xx 仅更新不新增，
+// 默认返回新增成员数，ch 为 true 时返回新增与分数变化的成员总数；互斥的标志组合（如 nx 与 gt）由 Redis 返回错误
+func (r *RedisClient) ZAddArgs(ctx context.Context, key string, gt, lt, nx, xx, ch bool, members ...redis.Z) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+	args := redis.ZAddArgs{NX: nx, XX: xx, LT: lt, GT: gt, Ch: ch, Members: members}
+	count, err := r.client.ZAddArgs(ctx, key, args).Result()
+	if err != nil {
+		return 0, r.errorf("cache: zadd %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
 
+// ZAddGT 写入成员且只提升不降低已有成员的分数，返回新增与分数变化的成员总数，适用于只增不减的排行榜
+func (r *RedisClient) ZAddGT(ctx context.Context, key string, members ...redis.Z) (int64, error) {
+	return r.ZAddArgs(ctx, key, true, false, false, false, true, members...)
+}
+
 // ZRem 从有序集合中删除指定成员
 func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
 	if len(members) == 0 {
@@ -339,7 +899,7 @@ func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface
 	}
 	count, err := r.client.ZRem(ctx, key, members...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: zrem %q: %w", key, err)
+		return 0, r.errorf("cache: zrem %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -348,7 +908,7 @@ func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface
 func (r *RedisClient) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
 	result, err := r.client.ZRange(ctx, key, start, stop).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: zrange %q: %w", key, err)
+		return nil, r.errorf("cache: zrange %q: %w", r.maskKey(key), err)
 	}
 	return result, nil
 }
@@ -357,7 +917,7 @@ func (r *RedisClient) ZRange(ctx context.Context, key string, start, stop int64)
 func (r *RedisClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
 	result, err := r.client.ZRevRange(ctx, key, start, stop).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: zrevrange %q: %w", key, err)
+		return nil, r.errorf("cache: zrevrange %q: %w", r.maskKey(key), err)
 	}
 	return result, nil
 }
@@ -366,25 +926,43 @@ func (r *RedisClient) ZRevRange(ctx context.Context, key string, start, stop int
 func (r *RedisClient) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
 	result, err := r.client.ZRangeByScore(ctx, key, opt).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: zrangebyscore %q: %w", key, err)
+		return nil, r.errorf("cache: zrangebyscore %q: %w", r.maskKey(key), err)
 	}
 	return result, nil
 }
 
+// ZRangeByScoreWindow 按分数窗口分页获取成员（升序），min/max 支持 "(" 开区间前缀与 "-inf"/"+inf"，
+// count 小于等于 0 时返回 offset 之后的全部成员，适用于按时间戳打分的事件查询
+func (r *RedisClient) ZRangeByScoreWindow(ctx context.Context, key, min, max string, offset, count int64) ([]string, error) {
+	if count <= 0 {
+		count = -1
+	}
+	return r.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max, Offset: offset, Count: count})
+}
+
 // ZRevRangeByScore 按分数区间获取有序集合成员（降序），支持分页
 func (r *RedisClient) ZRevRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
 	result, err := r.client.ZRevRangeByScore(ctx, key, opt).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: zrevrangebyscore %q: %w", key, err)
+		return nil, r.errorf("cache: zrevrangebyscore %q: %w", r.maskKey(key), err)
 	}
 	return result, nil
 }
 
+// ZRemRangeByScore 删除分数在 min 和 max 之间的成员并返回删除数量，区间语法同 ZRangeByScoreWindow，常用于清理过期事件
+func (r *RedisClient) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	count, err := r.client.ZRemRangeByScore(ctx, key, min, max).Result()
+	if err != nil {
+		return 0, r.errorf("cache: zremrangebyscore %q: %w", r.maskKey(key), err)
+	}
+	return count, nil
+}
+
 // ZCard 获取有序集合的成员数量
 func (r *RedisClient) ZCard(ctx context.Context, key string) (int64, error) {
 	count, err := r.client.ZCard(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: zcard %q: %w", key, err)
+		return 0, r.errorf("cache: zcard %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -393,7 +971,7 @@ func (r *RedisClient) ZCard(ctx context.Context, key string) (int64, error) {
 func (r *RedisClient) ZCount(ctx context.Context, key, min, max string) (int64, error) {
 	count, err := r.client.ZCount(ctx, key, min, max).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: zcount %q: %w", key, err)
+		return 0, r.errorf("cache: zcount %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -403,21 +981,54 @@ func (r *RedisClient) ZScore(ctx context.Context, key, member string) (float64,
 	score, err := r.client.ZScore(ctx, key, member).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, fmt.Errorf("cache: zscore %q:%q: %w", key, member, err)
+			return 0, r.errorf("cache: zscore %q:%q: %w", r.maskKey(key), member, err)
 		}
-		return 0, fmt.Errorf("cache: zscore %q:%q: %w", key, member, err)
+		return 0, r.errorf("cache: zscore %q:%q: %w", r.maskKey(key), member, err)
 	}
 	return score, nil
 }
 
+// ZPopMin 原子弹出分数最小的 count 个成员（按分数升序），集合为空时返回空切片
+func (r *RedisClient) ZPopMin(ctx context.Context, key string, count int64) ([]redis.Z, error) {
+	members, err := r.client.ZPopMin(ctx, key, count).Result()
+	if err != nil {
+		return nil, r.errorf("cache: zpopmin %q: %w", r.maskKey(key), err)
+	}
+	return members, nil
+}
+
+// ZPopMax 原子弹出分数最大的 count 个成员（按分数降序），集合为空时返回空切片
+func (r *RedisClient) ZPopMax(ctx context.Context, key string, count int64) ([]redis.Z, error) {
+	members, err := r.client.ZPopMax(ctx, key, count).Result()
+	if err != nil {
+		return nil, r.errorf("cache: zpopmax %q: %w", r.maskKey(key), err)
+	}
+	return members, nil
+}
+
+// BZPopMin 阻塞弹出 keys 中首个非空集合的最小分数成员，timeout 为 0 时一直阻塞；
+// ctx 截止时间早于 timeout 时以 ctx 为准（Redis 超时精度为秒，不足 1s 按 1s 计），超时返回包装 redis.Nil 的错误
+func (r *RedisClient) BZPopMin(ctx context.Context, timeout time.Duration, keys ...string) (redis.ZWithKey, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); timeout == 0 || remaining < timeout {
+			timeout = max(remaining, time.Second)
+		}
+	}
+	result, err := r.client.BZPopMin(ctx, timeout, keys...).Result()
+	if err != nil {
+		return redis.ZWithKey{}, r.errorf("cache: bzpopmin %v: %w", r.maskKeys(keys), err)
+	}
+	return *result, nil
+}
+
 // ZRank 获取有序集合中成员的升序排名（从 0 开始）
 func (r *RedisClient) ZRank(ctx context.Context, key, member string) (int64, error) {
 	rank, err := r.client.ZRank(ctx, key, member).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, fmt.Errorf("cache: zrank %q:%q: %w", key, member, err)
+			return 0, r.errorf("cache: zrank %q:%q: %w", r.maskKey(key), member, err)
 		}
-		return 0, fmt.Errorf("cache: zrank %q:%q: %w", key, member, err)
+		return 0, r.errorf("cache: zrank %q:%q: %w", r.maskKey(key), member, err)
 	}
 	return rank, nil
 }
@@ -427,9 +1038,9 @@ func (r *RedisClient) ZRevRank(ctx context.Context, key, member string) (int64,
 	rank, err := r.client.ZRevRank(ctx, key, member).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, fmt.Errorf("cache: zrevrank %q:%q: %w", key, member, err)
+			return 0, r.errorf("cache: zrevrank %q:%q: %w", r.maskKey(key), member, err)
 		}
-		return 0, fmt.Errorf("cache: zrevrank %q:%q: %w", key, member, err)
+		return 0, r.errorf("cache: zrevrank %q:%q: %w", r.maskKey(key), member, err)
 	}
 	return rank, nil
 }
@@ -438,7 +1049,7 @@ func (r *RedisClient) ZRevRank(ctx context.Context, key, member string) (int64,
 func (r *RedisClient) ZIncrBy(ctx context.Context, key, member string, increment float64) (float64, error) {
 	score, err := r.client.ZIncrBy(ctx, key, increment, member).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: zincrby %q:%q: %w", key, member, err)
+		return 0, r.errorf("cache: zincrby %q:%q: %w", r.maskKey(key), member, err)
 	}
 	return score, nil
 }
@@ -450,7 +1061,7 @@ func (r *RedisClient) LPush(ctx context.Context, key string, values ...interface
 	}
 	count, err := r.client.LPush(ctx, key, values...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: lpush %q: %w", key, err)
+		return 0, r.errorf("cache: lpush %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -462,7 +1073,7 @@ func (r *RedisClient) RPush(ctx context.Context, key string, values ...interface
 	}
 	count, err := r.client.RPush(ctx, key, values...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: rpush %q: %w", key, err)
+		return 0, r.errorf("cache: rpush %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -474,7 +1085,7 @@ func (r *RedisClient) LPop(ctx context.Context, key string) (string, error) {
 		return "", nil
 	}
 	if err != nil {
-		return "", fmt.Errorf("cache: lpop %q: %w", key, err)
+		return "", r.errorf("cache: lpop %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
@@ -486,7 +1097,7 @@ func (r *RedisClient) RPop(ctx context.Context, key string) (string, error) {
 		return "", nil
 	}
 	if err != nil {
-		return "", fmt.Errorf("cache: rpop %q: %w", key, err)
+		return "", r.errorf("cache: rpop %q: %w", r.maskKey(key), err)
 	}
 	return val, nil
 }
@@ -495,7 +1106,7 @@ func (r *RedisClient) RPop(ctx context.Context, key string) (string, error) {
 func (r *RedisClient) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
 	result, err := r.client.LRange(ctx, key, start, stop).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: lrange %q: %w", key, err)
+		return nil, r.errorf("cache: lrange %q: %w", r.maskKey(key), err)
 	}
 	return result, nil
 }
@@ -504,7 +1115,7 @@ func (r *RedisClient) LRange(ctx context.Context, key string, start, stop int64)
 func (r *RedisClient) LLen(ctx context.Context, key string) (int64, error) {
 	count, err := r.client.LLen(ctx, key).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: llen %q: %w", key, err)
+		return 0, r.errorf("cache: llen %q: %w", r.maskKey(key), err)
 	}
 	return count, nil
 }
@@ -513,15 +1124,41 @@ func (r *RedisClient) LLen(ctx context.Context, key string) (int64, error) {
 func (r *RedisClient) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
 	removed, err := r.client.LRem(ctx, key, count, value).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: lrem %q: %w", key, err)
+		return 0, r.errorf("cache: lrem %q: %w", r.maskKey(key), err)
 	}
 	return removed, nil
 }
 
+// LPos 返回列表中等于 value 的元素下标，rank 为正数时从头部起跳过前 rank-1 个匹配，为负数时从尾部反向查找，为 0 时按 1 处理；
+// count 为 0 时返回全部匹配，未找到时返回空切片
+func (r *RedisClient) LPos(ctx context.Context, key string, value string, rank, count int64) ([]int64, error) {
+	if rank == 0 {
+		rank = 1
+	}
+	positions, err := r.client.LPosCount(ctx, key, value, count, redis.LPosArgs{Rank: rank}).Result()
+	if err != nil {
+		return nil, r.errorf("cache: lpos %q: %w", r.maskKey(key), err)
+	}
+	return positions, nil
+}
+
+// LInsert 在 pivot 元素之前（before 为 true）或之后插入 value，返回插入后的列表长度，pivot 不存在时返回 -1，key 不存在时返回 0
+func (r *RedisClient) LInsert(ctx context.Context, key string, before bool, pivot, value interface{}) (int64, error) {
+	op := "AFTER"
+	if before {
+		op = "BEFORE"
+	}
+	length, err := r.client.LInsert(ctx, key, op, pivot, value).Result()
+	if err != nil {
+		return 0, r.errorf("cache: linsert %q: %w", r.maskKey(key), err)
+	}
+	return length, nil
+}
+
 // LTrim 保留列表指定区间内的元素，删除其余
 func (r *RedisClient) LTrim(ctx context.Context, key string, start, stop int64) error {
 	if err := r.client.LTrim(ctx, key, start, stop).Err(); err != nil {
-		return fmt.Errorf("cache: ltrim %q: %w", key, err)
+		return r.errorf("cache: ltrim %q: %w", r.maskKey(key), err)
 	}
 	return nil
 }
@@ -530,11 +1167,31 @@ func (r *RedisClient) LTrim(ctx context.Context, key string, start, stop int64)
 func (r *RedisClient) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
 	keys, nextCursor, err := r.client.Scan(ctx, cursor, match, count).Result()
 	if err != nil {
-		return nil, 0, fmt.Errorf("cache: scan %v: %w", cursor, err)
+		return nil, 0, r.errorf("cache: scan %v: %w", cursor, err)
 	}
 	return keys, nextCursor, nil
 }
 
+// DBSize 获取当前数据库中 key 的数量
+func (r *RedisClient) DBSize(ctx context.Context) (int64, error) {
+	size, err := r.client.DBSize(ctx).Result()
+	if err != nil {
+		return 0, r.errorf("cache: dbsize: %w", err)
+	}
+	return size, nil
+}
+
+// FlushDB 清空当前数据库，confirm 必须为 true 才会执行，防止误删数据
+func (r *RedisClient) FlushDB(ctx context.Context, confirm bool) error {
+	if !confirm {
+		return ErrFlushNotConfirmed
+	}
+	if err := r.client.FlushDB(ctx).Err(); err != nil {
+		return r.errorf("cache: flushdb: %w", err)
+	}
+	return nil
+}
+
 // Pipeline 返回 go-redis 管道实例，用于批量执行命令减少网络往返
 func (r *RedisClient) Pipeline() redis.Pipeliner {
 	return r.client.Pipeline()
@@ -544,7 +1201,7 @@ func (r *RedisClient) Pipeline() redis.Pipeliner {
 func (r *RedisClient) XAdd(ctx context.Context, values *redis.XAddArgs) (string, error) {
 	id, err := r.client.XAdd(ctx, values).Result()
 	if err != nil {
-		return "", fmt.Errorf("cache: xadd %q: %w", values.Stream, err)
+		return "", r.errorf("cache: xadd %q: %w", r.maskKey(values.Stream), err)
 	}
 	return id, nil
 }
@@ -556,7 +1213,7 @@ func (r *RedisClient) XRead(ctx context.Context, streams *redis.XReadArgs) ([]re
 		if errors.Is(err, redis.Nil) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("cache: xread: %w", err)
+		return nil, r.errorf("cache: xread: %w", err)
 	}
 	return result, nil
 }
@@ -568,7 +1225,7 @@ func (r *RedisClient) XReadGroup(ctx context.Context, groupConsumer *redis.XRead
 		if errors.Is(err, redis.Nil) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("cache: xreadgroup: %w", err)
+		return nil, r.errorf("cache: xreadgroup: %w", err)
 	}
 	return result, nil
 }
@@ -576,7 +1233,7 @@ func (r *RedisClient) XReadGroup(ctx context.Context, groupConsumer *redis.XRead
 // XGroupCreate 创建消费者组，$ 表示从最新消息开始消费，0 表示从头开始
 func (r *RedisClient) XGroupCreate(ctx context.Context, stream, group, start string) error {
 	if err := r.client.XGroupCreate(ctx, stream, group, start).Err(); err != nil {
-		return fmt.Errorf("cache: xgroup create %q %q: %w", stream, group, err)
+		return r.errorf("cache: xgroup create %q %q: %w", r.maskKey(stream), group, err)
 	}
 	return nil
 }
@@ -585,7 +1242,7 @@ func (r *RedisClient) XGroupCreate(ctx context.Context, stream, group, start str
 func (r *RedisClient) XGroupDestroy(ctx context.Context, stream, group string) (int64, error) {
 	count, err := r.client.XGroupDestroy(ctx, stream, group).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: xgroup destroy %q %q: %w", stream, group, err)
+		return 0, r.errorf("cache: xgroup destroy %q %q: %w", r.maskKey(stream), group, err)
 	}
 	return count, nil
 }
@@ -594,7 +1251,7 @@ func (r *RedisClient) XGroupDestroy(ctx context.Context, stream, group string) (
 func (r *RedisClient) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
 	count, err := r.client.XAck(ctx, stream, group, ids...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: xack %q %q: %w", stream, group, err)
+		return 0, r.errorf("cache: xack %q %q: %w", r.maskKey(stream), group, err)
 	}
 	return count, nil
 }
@@ -603,7 +1260,7 @@ func (r *RedisClient) XAck(ctx context.Context, stream, group string, ids ...str
 func (r *RedisClient) XDel(ctx context.Context, stream string, ids ...string) (int64, error) {
 	count, err := r.client.XDel(ctx, stream, ids...).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: xdel %q: %w", stream, err)
+		return 0, r.errorf("cache: xdel %q: %w", r.maskKey(stream), err)
 	}
 	return count, nil
 }
@@ -612,7 +1269,7 @@ func (r *RedisClient) XDel(ctx context.Context, stream string, ids ...string) (i
 func (r *RedisClient) XLen(ctx context.Context, stream string) (int64, error) {
 	count, err := r.client.XLen(ctx, stream).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: xlen %q: %w", stream, err)
+		return 0, r.errorf("cache: xlen %q: %w", r.maskKey(stream), err)
 	}
 	return count, nil
 }
@@ -621,7 +1278,7 @@ func (r *RedisClient) XLen(ctx context.Context, stream string) (int64, error) {
 func (r *RedisClient) XRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
 	result, err := r.client.XRange(ctx, stream, start, stop).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xrange %q: %w", stream, err)
+		return nil, r.errorf("cache: xrange %q: %w", r.maskKey(stream), err)
 	}
 	return result, nil
 }
@@ -630,7 +1287,7 @@ func (r *RedisClient) XRange(ctx context.Context, stream, start, stop string) ([
 func (r *RedisClient) XRevRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
 	result, err := r.client.XRevRange(ctx, stream, start, stop).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xrevrange %q: %w", stream, err)
+		return nil, r.errorf("cache: xrevrange %q: %w", r.maskKey(stream), err)
 	}
 	return result, nil
 }
@@ -639,7 +1296,7 @@ func (r *RedisClient) XRevRange(ctx context.Context, stream, start, stop string)
 func (r *RedisClient) XTrimMaxLen(ctx context.Context, stream string, maxLen int64) (int64, error) {
 	count, err := r.client.XTrimMaxLen(ctx, stream, maxLen).Result()
 	if err != nil {
-		return 0, fmt.Errorf("cache: xtrim %q: %w", stream, err)
+		return 0, r.errorf("cache: xtrim %q: %w", r.maskKey(stream), err)
 	}
 	return count, nil
 }
@@ -648,7 +1305,7 @@ func (r *RedisClient) XTrimMaxLen(ctx context.Context, stream string, maxLen int
 func (r *RedisClient) XPending(ctx context.Context, stream, group string) (*redis.XPending, error) {
 	result, err := r.client.XPending(ctx, stream, group).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xpending %q %q: %w", stream, group, err)
+		return nil, r.errorf("cache: xpending %q %q: %w", r.maskKey(stream), group, err)
 	}
 	return result, nil
 }
@@ -657,7 +1314,7 @@ func (r *RedisClient) XPending(ctx context.Context, stream, group string) (*redi
 func (r *RedisClient) XPendingExt(ctx context.Context, args *redis.XPendingExtArgs) ([]redis.XPendingExt, error) {
 	result, err := r.client.XPendingExt(ctx, args).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xpendingext %q %q: %w", args.Stream, args.Group, err)
+		return nil, r.errorf("cache: xpendingext %q %q: %w", r.maskKey(args.Stream), args.Group, err)
 	}
 	return result, nil
 }
@@ -666,7 +1323,7 @@ func (r *RedisClient) XPendingExt(ctx context.Context, args *redis.XPendingExtAr
 func (r *RedisClient) XClaim(ctx context.Context, args *redis.XClaimArgs) ([]redis.XMessage, error) {
 	result, err := r.client.XClaim(ctx, args).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xclaim %q %q: %w", args.Stream, args.Group, err)
+		return nil, r.errorf("cache: xclaim %q %q: %w", r.maskKey(args.Stream), args.Group, err)
 	}
 	return result, nil
 }
@@ -675,7 +1332,7 @@ func (r *RedisClient) XClaim(ctx context.Context, args *redis.XClaimArgs) ([]red
 func (r *RedisClient) XInfoStream(ctx context.Context, stream string) (*redis.XInfoStream, error) {
 	result, err := r.client.XInfoStream(ctx, stream).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xinfo stream %q: %w", stream, err)
+		return nil, r.errorf("cache: xinfo stream %q: %w", r.maskKey(stream), err)
 	}
 	return result, nil
 }
@@ -684,12 +1341,13 @@ func (r *RedisClient) XInfoStream(ctx context.Context, stream string) (*redis.XI
 func (r *RedisClient) XInfoGroups(ctx context.Context, stream string) ([]redis.XInfoGroup, error) {
 	result, err := r.client.XInfoGroups(ctx, stream).Result()
 	if err != nil {
-		return nil, fmt.Errorf("cache: xinfo groups %q: %w", stream, err)
+		return nil, r.errorf("cache: xinfo groups %q: %w", r.maskKey(stream), err)
 	}
 	return result, nil
 }
 
-// UniversalClient 返回底层 go-redis 客户端，供需要原生接口的组件使用
+// UniversalClient 返回底层 go-redis 客户端，供需要原生接口的组件使用或执行未封装的命令（如 OBJECT FREQ、CLIENT INFO），
+// 无需再创建第二个连接池；直接调用会绕过本封装的错误包装与空值处理等约定
 func (r *RedisClient) UniversalClient() redis.UniversalClient {
 	return r.client
 }
@@ -2,9 +2,21 @@ package cache
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,53 +31,6 @@ func TestRedisClient(t *testing.T) {
 	redisClient := NewRedisClient(client)
 	ctx := context.Background()
 
-	// 测试 Set 和 Get
-	t.Run("Test Set and Get", func(t *testing.T) {
-		key := "test_key"
-		value := "test_value"
-		// 设置值
-		err := redisClient.Set(ctx, key, value, 10*time.Second)
-		assert.Nil(t, err, "Should not return error while setting value")
-
-		// 获取值
-		got, err := redisClient.Get(ctx, key)
-		assert.Nil(t, err, "Should not return error while getting value")
-		assert.Equal(t, value, got, "The value should match")
-	})
-
-	// 测试 Del
-	t.Run("Test Del", func(t *testing.T) {
-		key := "test_del_key"
-		value := "to_be_deleted"
-
-		// 设置值
-		err := redisClient.Set(ctx, key, value, 10*time.Second)
-		assert.Nil(t, err, "Should not return error while setting value")
-
-		// 删除值
-		err = redisClient.Del(ctx, key)
-		assert.Nil(t, err, "Should not return error while deleting value")
-
-		// 尝试获取已删除的值
-		got, err := redisClient.Get(ctx, key)
-		assert.Nil(t, err, "Should not return error while getting deleted value")
-		assert.Empty(t, got, "The value should be empty after deletion")
-	})
-
-	// 测试 MSet 和 MGet
-	t.Run("Test MSet and MGet", func(t *testing.T) {
-		values := []interface{}{"key1", "value1", "key2", "value2"}
-
-		// 批量设置多个值
-		err := redisClient.MSet(ctx, values...)
-		assert.Nil(t, err, "Should not return error while setting multiple values")
-
-		// 批量获取多个值
-		got, err := redisClient.MGet(ctx, "key1", "key2")
-		assert.Nil(t, err, "Should not return error while getting multiple values")
-		assert.Equal(t, []interface{}{"value1", "value2"}, got, "The values should match")
-	})
-
 	// 测试 Expire 和 TTL
 	t.Run("Test Expire and TTL", func(t *testing.T) {
 		key := "test_ttl_key"
@@ -273,3 +238,1028 @@ func TestRedisClient(t *testing.T) {
 		assert.Nil(t, err, "Should not return error while cleaning up test data")
 	})
 }
+
+// TestRedisClientBasic 基于 miniredis 测试 Set/Get、Del、MSet/MGet，无需本地 Redis
+func TestRedisClientBasic(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	// 测试 Set 和 Get
+	t.Run("Test Set and Get", func(t *testing.T) {
+		key := "test_key"
+		value := "test_value"
+		// 设置值
+		err := redisClient.Set(ctx, key, value, 10*time.Second)
+		assert.Nil(t, err, "Should not return error while setting value")
+
+		// 获取值
+		got, err := redisClient.Get(ctx, key)
+		assert.Nil(t, err, "Should not return error while getting value")
+		assert.Equal(t, value, got, "The value should match")
+	})
+
+	// 测试 Del
+	t.Run("Test Del", func(t *testing.T) {
+		key := "test_del_key"
+		value := "to_be_deleted"
+
+		// 设置值
+		err := redisClient.Set(ctx, key, value, 10*time.Second)
+		assert.Nil(t, err, "Should not return error while setting value")
+
+		// 删除值
+		err = redisClient.Del(ctx, key)
+		assert.Nil(t, err, "Should not return error while deleting value")
+
+		// 尝试获取已删除的值
+		got, err := redisClient.Get(ctx, key)
+		assert.Nil(t, err, "Should not return error while getting deleted value")
+		assert.Empty(t, got, "The value should be empty after deletion")
+	})
+
+	// 测试 MSet 和 MGet
+	t.Run("Test MSet and MGet", func(t *testing.T) {
+		values := []interface{}{"key1", "value1", "key2", "value2"}
+
+		// 批量设置多个值
+		err := redisClient.MSet(ctx, values...)
+		assert.Nil(t, err, "Should not return error while setting multiple values")
+
+		// 批量获取多个值
+		got, err := redisClient.MGet(ctx, "key1", "key2")
+		assert.Nil(t, err, "Should not return error while getting multiple values")
+		assert.Equal(t, []interface{}{"value1", "value2"}, got, "The values should match")
+	})
+}
+
+// TestRedisClientPTTL 验证 1500ms 的 TTL 以 PX 写入，PTTL 保留毫秒精度而 TTL 按秒取整
+func TestRedisClientPTTL(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_pttl_key"
+
+	assert.Nil(t, redisClient.Set(ctx, key, "value", 1500*time.Millisecond))
+	pttl, err := redisClient.PTTL(ctx, key)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(1500*time.Millisecond), float64(pttl), float64(100*time.Millisecond))
+
+	ttl, err := redisClient.TTL(ctx, key)
+	assert.Nil(t, err)
+	assert.Contains(t, []time.Duration{time.Second, 2 * time.Second}, ttl)
+
+	missing, err := redisClient.PTTL(ctx, "test_pttl_missing")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(-2), missing)
+}
+
+// TestRedisClientHSetNX 验证 HSetNX 不覆盖已有字段、新字段写入成功，HRandField 返回已有字段
+func TestRedisClientHSetNX(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_hsetnx_key"
+
+	assert.Nil(t, redisClient.HSet(ctx, key, "existing", "old"))
+	created, err := redisClient.HSetNX(ctx, key, "existing", "new")
+	assert.Nil(t, err)
+	assert.False(t, created, "existing field should not be overwritten")
+	value, err := redisClient.HGet(ctx, key, "existing")
+	assert.Nil(t, err)
+	assert.Equal(t, "old", value)
+
+	created, err = redisClient.HSetNX(ctx, key, "fresh", "init")
+	assert.Nil(t, err)
+	assert.True(t, created, "new field should be created")
+	value, err = redisClient.HGet(ctx, key, "fresh")
+	assert.Nil(t, err)
+	assert.Equal(t, "init", value)
+
+	fields, err := redisClient.HRandField(ctx, key, 2)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"existing", "fresh"}, fields)
+
+	fields, err = redisClient.HRandField(ctx, "test_hsetnx_missing", 2)
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+}
+
+// TestRedisClientSAddBatched 验证 25 万成员按 1 万分批写入后 SCard 等于去重后的成员数
+func TestRedisClientSAddBatched(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_sadd_batched"
+
+	const total = 250000
+	members := make([]interface{}, 0, total)
+	unique := map[string]struct{}{}
+	for i := 0; i < total; i++ {
+		// 每 10 个成员中有一个与前一个重复，验证返回值只统计新增成员
+		member := fmt.Sprintf("user_%d", i)
+		if i%10 == 9 {
+			member = fmt.Sprintf("user_%d", i-1)
+		}
+		members = append(members, member)
+		unique[member] = struct{}{}
+	}
+
+	added, err := redisClient.SAddBatched(ctx, key, 10000, members...)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(unique)), added)
+	count, err := redisClient.SCard(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(unique)), count)
+
+	added, err = redisClient.SAddBatched(ctx, key, 0)
+	assert.Nil(t, err)
+	assert.Zero(t, added)
+}
+
+// TestRedisClientSetMany 验证批量写入时每个 key 使用独立的过期时间
+func TestRedisClientSetMany(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+
+	items := map[string]Item{
+		"test_setmany_a": {Value: "a", TTL: 10 * time.Second},
+		"test_setmany_b": {Value: "b", TTL: 20 * time.Second},
+		"test_setmany_c": {Value: "c", TTL: 30 * time.Second},
+	}
+	defer func() {
+		_ = redisClient.Del(ctx, "test_setmany_a", "test_setmany_b", "test_setmany_c")
+	}()
+
+	err := redisClient.SetMany(ctx, items)
+	assert.Nil(t, err, "Should not return error while setting many values")
+
+	for key, item := range items {
+		got, err := redisClient.Get(ctx, key)
+		assert.Nil(t, err, "Should not return error while getting value")
+		assert.Equal(t, item.Value, got, "The value should match")
+
+		ttl, err := redisClient.TTL(ctx, key)
+		assert.Nil(t, err, "Should not return error while getting ttl")
+		assert.True(t, ttl > item.TTL-2*time.Second && ttl <= item.TTL, "TTL of %s should be close to %v, got %v", key, item.TTL, ttl)
+	}
+}
+
+// TestRedisClientIncrByFloat 验证浮点自增在误差范围内累加正确
+func TestRedisClientIncrByFloat(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+
+	key := "test_incrbyfloat_key"
+	_ = redisClient.Del(ctx, key)
+	defer func() {
+		_ = redisClient.Del(ctx, key)
+	}()
+
+	var got float64
+	for i := 0; i < 10; i++ {
+		val, err := redisClient.IncrByFloat(ctx, key, 0.1)
+		assert.Nil(t, err, "Should not return error while incrementing float value")
+		got = val
+	}
+	assert.InDelta(t, 1.0, got, 1e-9, "The value should be approximately 1.0")
+}
+
+// TestRedisClientDBSize 验证写入 N 个 key 后 DBSize 随之增长
+func TestRedisClientDBSize(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379", DB: 15})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+
+	before, err := redisClient.DBSize(ctx)
+	assert.Nil(t, err, "Should not return error while getting db size")
+
+	keys := []string{"test_dbsize_1", "test_dbsize_2", "test_dbsize_3", "test_dbsize_4", "test_dbsize_5"}
+	_ = redisClient.Del(ctx, keys...)
+	defer func() {
+		_ = redisClient.Del(ctx, keys...)
+	}()
+	for _, key := range keys {
+		assert.Nil(t, redisClient.Set(ctx, key, "v", time.Minute), "Should not return error while setting value")
+	}
+
+	after, err := redisClient.DBSize(ctx)
+	assert.Nil(t, err, "Should not return error while getting db size")
+	assert.Equal(t, before+int64(len(keys)), after, "DBSize should grow by the number of seeded keys")
+}
+
+// TestRedisClientFlushDB 验证 FlushDB 需要显式确认才会清空数据库
+func TestRedisClientFlushDB(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379", DB: 15})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+
+	err := redisClient.Set(ctx, "test_flushdb_key", "v", time.Minute)
+	assert.Nil(t, err, "Should not return error while setting value")
+
+	err = redisClient.FlushDB(ctx, false)
+	assert.ErrorIs(t, err, ErrFlushNotConfirmed, "FlushDB should refuse to run without confirmation")
+	count, err := redisClient.Exists(ctx, "test_flushdb_key")
+	assert.Nil(t, err, "Should not return error while checking key existence")
+	assert.Equal(t, int64(1), count, "The key should survive an unconfirmed flush")
+
+	err = redisClient.FlushDB(ctx, true)
+	assert.Nil(t, err, "Should not return error while flushing confirmed")
+	size, err := redisClient.DBSize(ctx)
+	assert.Nil(t, err, "Should not return error while getting db size")
+	assert.Equal(t, int64(0), size, "The database should be empty after flush")
+}
+
+// TestRedisClientSIsMembersPipelined 验证管道批量成员检查的结果顺序与逐个检查一致
+func TestRedisClientSIsMembersPipelined(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	setKey := "test_sismembers_pipelined"
+	defer func() {
+		_ = redisClient.Del(ctx, setKey)
+	}()
+
+	_, err := redisClient.SAdd(ctx, setKey, 1, 3, 5)
+	assert.Nil(t, err, "Should not return error while adding members")
+
+	result, err := redisClient.SIsMembersPipelined(ctx, setKey, 1, 2, 3, 4, 5)
+	assert.Nil(t, err, "Should not return error while checking members")
+	assert.Equal(t, []bool{true, false, true, false, true}, result, "Membership should match input order")
+
+	empty, err := redisClient.SIsMembersPipelined(ctx, setKey)
+	assert.Nil(t, err, "Should not return error for empty members")
+	assert.Empty(t, empty, "Empty members should return empty result")
+}
+
+// setupSIsMemberBenchmark 准备成员检查基准测试的数据集与查询列表
+func setupSIsMemberBenchmark(b *testing.B) (*RedisClient, string, []interface{}) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(b, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	setKey := "bench_sismember"
+	startID, count, batchCount := 1000, 100000, 1000
+
+	userIDs := make([]interface{}, count)
+	for i := 0; i < count; i++ {
+		userIDs[i] = startID + i
+	}
+	if _, err := redisClient.SAdd(ctx, setKey, userIDs...); err != nil {
+		b.Fatalf("seed set: %v", err)
+	}
+	b.Cleanup(func() {
+		_ = redisClient.Del(ctx, setKey)
+	})
+
+	members := make([]interface{}, batchCount)
+	for i := 0; i < batchCount; i++ {
+		members[i] = startID + (i * count / batchCount)
+	}
+	return redisClient, setKey, members
+}
+
+// BenchmarkSIsMemberLoop 逐个 SISMEMBER 检查 1000 个成员，每次一次网络往返
+func BenchmarkSIsMemberLoop(b *testing.B) {
+	redisClient, setKey, members := setupSIsMemberBenchmark(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, member := range members {
+			if _, err := redisClient.SIsMember(ctx, setKey, member); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkSIsMembersPipelined 通过单次管道检查 1000 个成员
+func BenchmarkSIsMembersPipelined(b *testing.B) {
+	redisClient, setKey, members := setupSIsMemberBenchmark(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := redisClient.SIsMembersPipelined(ctx, setKey, members...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestRedisClientGetAny 验证 GetAny 对纯字符串、JSON 对象与数组返回对应的 Go 类型
+func TestRedisClientGetAny(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	keys := []string{"test_getany_plain", "test_getany_object", "test_getany_array"}
+	defer func() {
+		_ = redisClient.Del(ctx, keys...)
+	}()
+
+	assert.Nil(t, redisClient.Set(ctx, keys[0], "hello world", time.Minute))
+	assert.Nil(t, redisClient.Set(ctx, keys[1], `{"name":"alice","age":30}`, time.Minute))
+	assert.Nil(t, redisClient.Set(ctx, keys[2], `[1,"two",true]`, time.Minute))
+
+	plain, found, err := redisClient.GetAny(ctx, keys[0])
+	assert.Nil(t, err, "Should not return error while getting plain string")
+	assert.True(t, found, "The plain string key should exist")
+	assert.Equal(t, "hello world", plain, "Invalid JSON should fall back to raw string")
+
+	object, found, err := redisClient.GetAny(ctx, keys[1])
+	assert.Nil(t, err, "Should not return error while getting JSON object")
+	assert.True(t, found, "The JSON object key should exist")
+	assert.Equal(t, map[string]any{"name": "alice", "age": float64(30)}, object)
+
+	array, found, err := redisClient.GetAny(ctx, keys[2])
+	assert.Nil(t, err, "Should not return error while getting JSON array")
+	assert.True(t, found, "The JSON array key should exist")
+	assert.Equal(t, []any{float64(1), "two", true}, array)
+
+	missing, found, err := redisClient.GetAny(ctx, "test_getany_missing")
+	assert.Nil(t, err, "Missing key should not return error")
+	assert.False(t, found, "Missing key should report not found")
+	assert.Nil(t, missing)
+}
+
+// TestRedisClientUniversalClient 验证 UniversalClient 返回构造时传入的客户端
+func TestRedisClientUniversalClient(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	redisClient := NewRedisClient(client)
+	assert.Same(t, client, redisClient.UniversalClient(), "UniversalClient should return the wrapped client")
+}
+
+// TestMGetJSON 验证批量解码存在的 JSON 对象、跳过缺失 key，并标明解码失败的 key
+func TestMGetJSON(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	keys := []string{"test_mgetjson_1", "test_mgetjson_2", "test_mgetjson_3", "test_mgetjson_missing"}
+	defer func() {
+		_ = redisClient.Del(ctx, append(keys, "test_mgetjson_bad")...)
+	}()
+	for i, name := range []string{"alice", "bob", "carol"} {
+		assert.Nil(t, redisClient.Set(ctx, keys[i], fmt.Sprintf(`{"id":%d,"name":%q}`, i+1, name), time.Minute))
+	}
+
+	users, err := MGetJSON[user](ctx, redisClient, keys...)
+	assert.Nil(t, err, "Should not return error while batch decoding")
+	assert.Equal(t, map[string]user{
+		"test_mgetjson_1": {ID: 1, Name: "alice"},
+		"test_mgetjson_2": {ID: 2, Name: "bob"},
+		"test_mgetjson_3": {ID: 3, Name: "carol"},
+	}, users, "Missing keys should be skipped")
+
+	assert.Nil(t, redisClient.Set(ctx, "test_mgetjson_bad", "not json", time.Minute))
+	users, err = MGetJSON[user](ctx, redisClient, "test_mgetjson_1", "test_mgetjson_bad")
+	assert.ErrorContains(t, err, "test_mgetjson_bad", "Decode error should name the failing key")
+	assert.Len(t, users, 1, "Valid keys should still be decoded")
+}
+
+// TestRedisClientGetExists 验证 GetExists 区分缺失 key、空字符串与普通值
+func TestRedisClientGetExists(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	defer func() {
+		_ = redisClient.Del(ctx, "test_getexists_empty", "test_getexists_value")
+	}()
+
+	val, found, err := redisClient.GetExists(ctx, "test_getexists_missing")
+	assert.Nil(t, err, "Missing key should not return error")
+	assert.False(t, found, "Missing key should report not found")
+	assert.Equal(t, "", val)
+
+	assert.Nil(t, redisClient.Set(ctx, "test_getexists_empty", "", time.Minute))
+	val, found, err = redisClient.GetExists(ctx, "test_getexists_empty")
+	assert.Nil(t, err, "Empty value should not return error")
+	assert.True(t, found, "Empty string value should report found")
+	assert.Equal(t, "", val)
+
+	assert.Nil(t, redisClient.Set(ctx, "test_getexists_value", "v", time.Minute))
+	val, found, err = redisClient.GetExists(ctx, "test_getexists_value")
+	assert.Nil(t, err, "Normal value should not return error")
+	assert.True(t, found, "Normal value should report found")
+	assert.Equal(t, "v", val)
+}
+
+// TestRedisClientDelBatched 验证分批 UNLINK 删除 5000 个 key 并返回总删除数量
+func TestRedisClientDelBatched(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+
+	keys := make([]string, 5000)
+	items := make(map[string]Item, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("test_delbatched_%d", i)
+		items[keys[i]] = Item{Value: i, TTL: time.Minute}
+	}
+	assert.Nil(t, redisClient.SetMany(ctx, items), "Should not return error while seeding keys")
+
+	removed, err := redisClient.DelBatched(ctx, append(keys, "test_delbatched_missing"), 500)
+	assert.Nil(t, err, "Should not return error while deleting in batches")
+	assert.Equal(t, int64(len(keys)), removed, "Should report the number of removed keys")
+
+	count, err := redisClient.Exists(ctx, keys...)
+	assert.Nil(t, err, "Should not return error while checking key existence")
+	assert.Equal(t, int64(0), count, "All keys should be removed")
+}
+
+// TestRedisClientSInterCard 验证交集计数与 limit 提前截断
+func TestRedisClientSInterCard(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	keys := []string{"test_sintercard_a", "test_sintercard_b"}
+	defer func() {
+		_ = redisClient.Del(ctx, keys...)
+	}()
+
+	_, err := redisClient.SAdd(ctx, keys[0], 1, 2, 3, 4, 5, 6)
+	assert.Nil(t, err, "Should not return error while adding members")
+	_, err = redisClient.SAdd(ctx, keys[1], 4, 5, 6, 7, 8)
+	assert.Nil(t, err, "Should not return error while adding members")
+
+	count, err := redisClient.SInterCard(ctx, 0, keys...)
+	assert.Nil(t, err, "Should not return error while counting intersection")
+	assert.Equal(t, int64(3), count, "The intersection should contain 3 members")
+
+	count, err = redisClient.SInterCard(ctx, 2, keys...)
+	assert.Nil(t, err, "Should not return error while counting with limit")
+	assert.Equal(t, int64(2), count, "The count should be capped by limit")
+}
+
+// TestRedisClientCAS 验证比较并交换的成功、并发修改导致的失败以及新 key 写入
+func TestRedisClientCAS(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	key := "test_cas_state"
+	_ = redisClient.Del(ctx, key)
+	defer func() {
+		_ = redisClient.Del(ctx, key)
+	}()
+
+	swapped, err := redisClient.CAS(ctx, key, "", "v1", time.Minute)
+	assert.Nil(t, err, "Should not return error while setting fresh key")
+	assert.True(t, swapped, "Fresh key should be set when expected is empty")
+	ttl, err := redisClient.TTL(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0, "CAS should apply ttl")
+
+	swapped, err = redisClient.CAS(ctx, key, "v1", "v2", time.Minute)
+	assert.Nil(t, err, "Should not return error while swapping")
+	assert.True(t, swapped, "Swap should succeed when value matches")
+
+	// 模拟并发修改：读取到 v2 后，其他客户端已将值改为 v3
+	assert.Nil(t, redisClient.Set(ctx, key, "v3", time.Minute))
+	swapped, err = redisClient.CAS(ctx, key, "v2", "v4", time.Minute)
+	assert.Nil(t, err, "Should not return error on failed swap")
+	assert.False(t, swapped, "Swap should fail after concurrent change")
+	val, err := redisClient.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, "v3", val, "Value should be left untouched on failed swap")
+
+	swapped, err = redisClient.CAS(ctx, key, "", "v5", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, swapped, "Empty expected should fail when key exists")
+}
+
+// TestRedisClientExpireWithFlags 验证 GT 不缩短更长的过期时间，NX 在已有过期时间时拒绝修改
+func TestRedisClientExpireWithFlags(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	key := "test_expire_flags_session"
+	defer func() {
+		_ = redisClient.Del(ctx, key)
+	}()
+	assert.Nil(t, redisClient.Set(ctx, key, "session", time.Hour))
+
+	changed, err := redisClient.ExpireWithFlags(ctx, key, time.Minute, ExpireGT)
+	assert.Nil(t, err, "Should not return error while expiring with GT")
+	assert.False(t, changed, "GT should not shorten a longer ttl")
+	ttl, err := redisClient.TTL(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, ttl > time.Minute, "TTL should remain about one hour, got %v", ttl)
+
+	changed, err = redisClient.ExpireWithFlags(ctx, key, 2*time.Hour, ExpireGT)
+	assert.Nil(t, err)
+	assert.True(t, changed, "GT should extend a shorter ttl")
+
+	changed, err = redisClient.ExpireWithFlags(ctx, key, time.Minute, ExpireNX)
+	assert.Nil(t, err, "Should not return error while expiring with NX")
+	assert.False(t, changed, "NX should refuse when a ttl exists")
+}
+
+// TestRedisClientSMembersFunc 验证逐个回调遍历全部成员，回调返回错误时提前停止
+func TestRedisClientSMembersFunc(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	setKey := "test_smembers_func"
+	defer func() {
+		_ = redisClient.Del(ctx, setKey)
+	}()
+
+	members := make([]interface{}, 2500)
+	for i := range members {
+		members[i] = i
+	}
+	_, err := redisClient.SAdd(ctx, setKey, members...)
+	assert.Nil(t, err, "Should not return error while adding members")
+
+	seen := make(map[string]struct{})
+	err = redisClient.SMembersFunc(ctx, setKey, func(member string) error {
+		seen[member] = struct{}{}
+		return nil
+	})
+	assert.Nil(t, err, "Should not return error while iterating members")
+	assert.Len(t, seen, len(members), "Every member should be visited")
+
+	stopErr := errors.New("stop")
+	visited := 0
+	err = redisClient.SMembersFunc(ctx, setKey, func(string) error {
+		visited++
+		if visited == 10 {
+			return stopErr
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stopErr, "Callback error should stop iteration")
+	assert.Equal(t, 10, visited, "Iteration should stop right after the failing callback")
+}
+
+// BenchmarkSMembers 一次性获取 10 万成员的集合
+func BenchmarkSMembers(b *testing.B) {
+	redisClient, setKey, _ := setupSIsMemberBenchmark(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		members, err := redisClient.SMembers(ctx, setKey)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = len(members)
+	}
+}
+
+// BenchmarkSMembersFunc 通过 SSCAN 分页遍历 10 万成员的集合，B/op 为累计分配，峰值仅持有单页成员
+func BenchmarkSMembersFunc(b *testing.B) {
+	redisClient, setKey, _ := setupSIsMemberBenchmark(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		err := redisClient.SMembersFunc(ctx, setKey, func(string) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestRedisClientClose 验证 Close 关闭底层连接池，NewRedisClientFromOptions 与注入的客户端行为一致
+func TestRedisClientClose(t *testing.T) {
+	ctx := context.Background()
+	owned, err := NewRedisClientFromOptions(ctx, &redis.Options{Addr: "localhost:6379"})
+	if err != nil {
+		t.Skipf("redis not available: %v", err)
+	}
+	assert.Nil(t, owned.Set(ctx, "test_close_owned", "v", time.Minute))
+	assert.Nil(t, owned.Del(ctx, "test_close_owned"))
+	assert.Nil(t, owned.Close(), "Should not return error while closing owned client")
+	_, err = owned.Get(ctx, "test_close_owned")
+	assert.ErrorIs(t, err, redis.ErrClosed, "Operations should fail after Close")
+
+	injected := NewRedisClient(redis.NewClient(&redis.Options{Addr: "localhost:6379"}))
+	assert.Nil(t, injected.Close(), "Should not return error while closing injected client")
+	_, err = injected.Get(ctx, "test_close_injected")
+	assert.ErrorIs(t, err, redis.ErrClosed, "Close should close an injected client as before")
+}
+
+// TestRedisClientSetManyNX 验证批量写入只写入不存在的 key，已存在的 key 不被覆盖
+func TestRedisClientSetManyNX(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	keys := []string{"test_setmanynx_a", "test_setmanynx_b", "test_setmanynx_c"}
+	_ = redisClient.Del(ctx, keys...)
+	defer func() {
+		_ = redisClient.Del(ctx, keys...)
+	}()
+	assert.Nil(t, redisClient.Set(ctx, keys[0], "override", time.Minute))
+
+	written, err := redisClient.SetManyNX(ctx, map[string]Item{
+		keys[0]: {Value: "default", TTL: time.Minute},
+		keys[1]: {Value: "default", TTL: time.Minute},
+		keys[2]: {Value: "default", TTL: time.Minute},
+	})
+	assert.Nil(t, err, "Should not return error while seeding defaults")
+	assert.Equal(t, map[string]bool{keys[0]: false, keys[1]: true, keys[2]: true}, written)
+
+	val, err := redisClient.Get(ctx, keys[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "override", val, "Existing key should be left untouched")
+	val, err = redisClient.Get(ctx, keys[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "default", val, "New key should be written")
+}
+
+// TestRedisClientCountMatching 验证按前缀模式统计集合成员数量
+func TestRedisClientCountMatching(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	setKey := "test_count_matching"
+	defer func() {
+		_ = redisClient.Del(ctx, setKey)
+	}()
+
+	_, err := redisClient.SAdd(ctx, setKey, "a:1", "a:2", "b:1")
+	assert.Nil(t, err, "Should not return error while adding members")
+
+	count, err := redisClient.CountMatching(ctx, setKey, "a:*")
+	assert.Nil(t, err, "Should not return error while counting members")
+	assert.Equal(t, int64(2), count, "Only members with prefix a: should be counted")
+}
+
+// TestRedisClientZAddArgs 验证 GT 不会降低已有成员的较高分数，NX 不会更新已有成员
+func TestRedisClientZAddArgs(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_zaddargs_key"
+
+	_, err = redisClient.ZAdd(ctx, key, redis.Z{Score: 100, Member: "alice"})
+	assert.Nil(t, err)
+
+	changed, err := redisClient.ZAddGT(ctx, key, redis.Z{Score: 50, Member: "alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), changed, "GT should not lower a higher score")
+	score, err := redisClient.ZScore(ctx, key, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(100), score)
+
+	changed, err = redisClient.ZAddGT(ctx, key, redis.Z{Score: 150, Member: "alice"}, redis.Z{Score: 10, Member: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), changed, "CH should count both raised and added members")
+	score, err = redisClient.ZScore(ctx, key, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(150), score)
+
+	added, err := redisClient.ZAddArgs(ctx, key, false, false, true, false, false,
+		redis.Z{Score: 1, Member: "alice"}, redis.Z{Score: 20, Member: "carol"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), added, "NX should only add new members")
+	score, err = redisClient.ZScore(ctx, key, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(150), score, "NX should not update an existing member")
+}
+
+// TestRedisClientZScoreWindow 验证按时间戳打分的事件可按分数窗口分页查询，并按分数清理过期事件
+func TestRedisClientZScoreWindow(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_zscore_window_events"
+
+	base := int64(1_700_000_000)
+	for i := int64(0); i < 6; i++ {
+		_, err := redisClient.ZAdd(ctx, key, redis.Z{Score: float64(base + i*10), Member: fmt.Sprintf("event-%d", i)})
+		assert.Nil(t, err)
+	}
+	score, err := redisClient.ZIncrBy(ctx, key, "event-0", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(base+5), score)
+
+	events, err := redisClient.ZRangeByScoreWindow(ctx, key, strconv.FormatInt(base+10, 10), strconv.FormatInt(base+40, 10), 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"event-1", "event-2", "event-3", "event-4"}, events)
+
+	events, err = redisClient.ZRangeByScoreWindow(ctx, key, "("+strconv.FormatInt(base+10, 10), "+inf", 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"event-3", "event-4"}, events, "exclusive min with offset/count paging")
+
+	removed, err := redisClient.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(base+20, 10))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), removed)
+	events, err = redisClient.ZRangeByScoreWindow(ctx, key, "-inf", "+inf", 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"event-2", "event-3", "event-4", "event-5"}, events)
+}
+
+// TestRedisClientSMembersInt 验证整数集合解析为 []int64，存在非整数成员时返回错误
+func TestRedisClientSMembersInt(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err = redisClient.SAdd(ctx, "test_smembers_int", 1001, 1002, 1003)
+	assert.Nil(t, err)
+	ids, err := redisClient.SMembersInt(ctx, "test_smembers_int")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []int64{1001, 1002, 1003}, ids)
+
+	_, err = redisClient.SAdd(ctx, "test_smembers_mixed", 1, "two", 3)
+	assert.Nil(t, err)
+	ids, err = redisClient.SMembersInt(ctx, "test_smembers_mixed")
+	assert.Nil(t, ids)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Contains(t, err.Error(), `"two"`)
+}
+
+// newSelfSignedTLS 生成 localhost 自签名证书，返回服务端配置和信任该证书的客户端配置
+func newSelfSignedTLS(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	serverCfg := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	clientCfg := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+	return serverCfg, clientCfg
+}
+
+// TestNewSecureClient 验证 TLS + ACL 认证连接成功，认证失败时返回错误
+func TestNewSecureClient(t *testing.T) {
+	serverCfg, clientCfg := newSelfSignedTLS(t)
+	server, err := miniredis.RunTLS(serverCfg)
+	if err != nil {
+		t.Fatalf("start tls redis: %v", err)
+	}
+	defer server.Close()
+	server.RequireUserAuth("app", "s3cret")
+	ctx := context.Background()
+
+	redisClient, err := NewSecureClient(ctx, server.Addr(), "app", "s3cret", clientCfg)
+	assert.Nil(t, err)
+	defer redisClient.Close()
+	assert.Nil(t, redisClient.Set(ctx, "test_secure_key", "v", time.Minute))
+	value, err := redisClient.Get(ctx, "test_secure_key")
+	assert.Nil(t, err)
+	assert.Equal(t, "v", value)
+
+	_, err = NewSecureClient(ctx, server.Addr(), "app", "wrong", clientCfg)
+	assert.NotNil(t, err, "wrong password should fail the ping")
+}
+
+// TestRedisClientZPop 验证优先级队列按分数顺序弹出，阻塞弹出在空集合上超时返回 redis.Nil
+func TestRedisClientZPop(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_zpop_queue"
+
+	_, err = redisClient.ZAdd(ctx, key,
+		redis.Z{Score: 3, Member: "low"},
+		redis.Z{Score: 1, Member: "urgent"},
+		redis.Z{Score: 2, Member: "normal"},
+	)
+	assert.Nil(t, err)
+
+	popped, err := redisClient.ZPopMin(ctx, key, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []redis.Z{{Score: 1, Member: "urgent"}}, popped)
+	popped, err = redisClient.ZPopMax(ctx, key, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []redis.Z{{Score: 3, Member: "low"}}, popped)
+	item, err := redisClient.BZPopMin(ctx, time.Second, "test_zpop_empty", key)
+	assert.Nil(t, err)
+	assert.Equal(t, key, item.Key)
+	assert.Equal(t, "normal", item.Member)
+
+	popped, err = redisClient.ZPopMin(ctx, key, 1)
+	assert.Nil(t, err)
+	assert.Empty(t, popped)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = redisClient.BZPopMin(timeoutCtx, 0, key)
+	assert.ErrorIs(t, err, redis.Nil)
+}
+
+// TestRedisClientHGetAllMany 验证单次调用加载多个哈希，缺失的 key 不出现在结果中
+func TestRedisClientHGetAllMany(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Nil(t, redisClient.HSet(ctx, "test_hgetall_many:1", "name", "alice", "age", "30"))
+	assert.Nil(t, redisClient.HSet(ctx, "test_hgetall_many:2", "name", "bob"))
+	assert.Nil(t, redisClient.HSet(ctx, "test_hgetall_many:3", "name", "carol", "city", "paris"))
+
+	result, err := redisClient.HGetAllMany(ctx, "test_hgetall_many:1", "test_hgetall_many:missing", "test_hgetall_many:2", "test_hgetall_many:3")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"test_hgetall_many:1": {"name": "alice", "age": "30"},
+		"test_hgetall_many:2": {"name": "bob"},
+		"test_hgetall_many:3": {"name": "carol", "city": "paris"},
+	}, result)
+
+	empty, err := redisClient.HGetAllMany(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, empty)
+}
+
+// TestRedisClientSetRangeGetRange 验证 SetRange 覆盖定长值的中间字节，GetRange 读回修改后的区间
+func TestRedisClientSetRangeGetRange(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Nil(t, redisClient.Set(ctx, "test_setrange", "0123456789", 0))
+	length, err := redisClient.SetRange(ctx, "test_setrange", 3, "abcd")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), length)
+
+	patched, err := redisClient.GetRange(ctx, "test_setrange", 3, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, "abcd", patched)
+	whole, err := redisClient.Get(ctx, "test_setrange")
+	assert.Nil(t, err)
+	assert.Equal(t, "012abcd789", whole)
+
+	tail, err := redisClient.GetRange(ctx, "test_setrange", -3, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, "789", tail)
+	missing, err := redisClient.GetRange(ctx, "test_setrange:missing", 0, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, "", missing)
+}
+
+// TestRedisClientPing 验证 Ping 在服务可用时成功、连接关闭后返回错误
+func TestRedisClientPing(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	ctx := context.Background()
+	assert.Nil(t, redisClient.Ping(ctx))
+	cleanup()
+	assert.NotNil(t, redisClient.Ping(ctx))
+}
+
+// TestRedisClientExistsEach 验证按 key 返回存在状态，缺失的 key 为 false
+func TestRedisClientExistsEach(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Nil(t, redisClient.Set(ctx, "test_exists_each:1", "a", 0))
+	assert.Nil(t, redisClient.Set(ctx, "test_exists_each:3", "c", 0))
+
+	result, err := redisClient.ExistsEach(ctx, "test_exists_each:1", "test_exists_each:2", "test_exists_each:3")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{
+		"test_exists_each:1": true,
+		"test_exists_each:2": false,
+		"test_exists_each:3": true,
+	}, result)
+
+	empty, err := redisClient.ExistsEach(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, empty)
+}
+
+// TestRedisClientRecencyList 验证借助 LPos/LRem/LInsert 维护去重的最近浏览列表，重复浏览的条目移到头部
+func TestRedisClientRecencyList(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_recently_viewed"
+
+	_, err = redisClient.LPush(ctx, key, "item-a", "item-b", "item-c")
+	assert.Nil(t, err)
+	positions, err := redisClient.LPos(ctx, key, "item-a", 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, positions)
+
+	// 重新浏览 item-a：去重后插入到当前头部元素之前
+	removed, err := redisClient.LRem(ctx, key, 0, "item-a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), removed)
+	head, err := redisClient.LRange(ctx, key, 0, 0)
+	assert.Nil(t, err)
+	length, err := redisClient.LInsert(ctx, key, true, head[0], "item-a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), length)
+
+	items, err := redisClient.LRange(ctx, key, 0, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"item-a", "item-c", "item-b"}, items)
+	positions, err = redisClient.LPos(ctx, key, "item-a", 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0}, positions)
+
+	length, err = redisClient.LInsert(ctx, key, false, "missing", "item-d")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), length, "missing pivot should not insert")
+	positions, err = redisClient.LPos(ctx, key, "item-d", 0, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, positions)
+}
+
+// TestRedisClientMGetResults 验证批量读取结果与 key 一一对应，缺失的 key 标记为未找到
+func TestRedisClientMGetResults(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Nil(t, redisClient.Set(ctx, "test_mget_results_a", "alpha", time.Minute))
+	assert.Nil(t, redisClient.Set(ctx, "test_mget_results_b", "", time.Minute))
+
+	results, err := redisClient.MGetResults(ctx, "test_mget_results_a", "test_mget_results_missing", "test_mget_results_b")
+	assert.Nil(t, err)
+	assert.Equal(t, []Result{
+		{Key: "test_mget_results_a", Value: "alpha", Found: true},
+		{Key: "test_mget_results_missing"},
+		{Key: "test_mget_results_b", Value: "", Found: true},
+	}, results)
+
+	results, err = redisClient.MGetResults(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
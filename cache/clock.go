@@ -0,0 +1,25 @@
+package cache
+
+import "time"
+
+// Clock 时间源，依赖当前时间的组件（如 WindowCounter）通过它获取时间，测试中可注入假时钟
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock 使用系统时间的默认时钟
+type realClock struct{}
+
+// Now 返回系统当前时间
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock 替换 RedisClient 使用的时钟，传入 nil 时保持默认系统时钟
+func WithClock(clk Clock) RedisClientOption {
+	return func(r *RedisClient) {
+		if clk != nil {
+			r.clock = clk
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Codec 对象序列化编解码器，SetObject/GetObject 通过它读写 value，可替换为 msgpack、protobuf 等紧凑格式
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// jsonCodec 默认的 JSON 编解码器
+type jsonCodec struct{}
+
+// Marshal JSON 编码
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal JSON 解码
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithCodec 设置 SetObject/GetObject 使用的编解码器，传入 nil 时保持默认 JSON
+func WithCodec(codec Codec) RedisClientOption {
+	return func(r *RedisClient) {
+		if codec != nil {
+			r.codec = codec
+		}
+	}
+}
+
+// SetObject 使用配置的编解码器序列化 v 并写入 key
+func (r *RedisClient) SetObject(ctx context.Context, key string, v any, ttl time.Duration) error {
+	data, err := r.codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache: set object %q: %w", r.maskKey(key), err)
+	}
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return r.errorf("cache: set object %q: %w", r.maskKey(key), err)
+	}
+	return nil
+}
+
+// GetObject 读取 key 并使用配置的编解码器反序列化到 dst，dst 须为指针；found 为 false 表示 key 不存在，此时 dst 保持不变
+func (r *RedisClient) GetObject(ctx context.Context, key string, dst any) (bool, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, r.errorf("cache: get object %q: %w", r.maskKey(key), err)
+	}
+	if err := r.codec.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("cache: get object %q: %w", r.maskKey(key), err)
+	}
+	return true, nil
+}
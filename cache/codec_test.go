@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prefixCodec 在 JSON 外层加前缀并记录调用次数的测试编解码器
+type prefixCodec struct {
+	marshals   int
+	unmarshals int
+}
+
+func (codec *prefixCodec) Marshal(v any) ([]byte, error) {
+	codec.marshals++
+	data, err := jsonCodec{}.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("fake:"), data...), nil
+}
+
+func (codec *prefixCodec) Unmarshal(data []byte, v any) error {
+	codec.unmarshals++
+	return jsonCodec{}.Unmarshal([]byte(strings.TrimPrefix(string(data), "fake:")), v)
+}
+
+// TestRedisClientObjectCodec 验证 SetObject/GetObject 默认使用 JSON，配置 WithCodec 后经自定义编解码器往返
+func TestRedisClientObjectCodec(t *testing.T) {
+	type profile struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	ctx := context.Background()
+
+	t.Run("default json", func(t *testing.T) {
+		redisClient, cleanup, err := NewTestClient()
+		if err != nil {
+			t.Fatalf("start test redis: %v", err)
+		}
+		defer cleanup()
+
+		assert.Nil(t, redisClient.SetObject(ctx, "test_object", profile{Name: "alice", Age: 30}, time.Minute))
+		raw, err := redisClient.Get(ctx, "test_object")
+		assert.Nil(t, err)
+		assert.Equal(t, `{"name":"alice","age":30}`, raw)
+
+		var got profile
+		found, err := redisClient.GetObject(ctx, "test_object", &got)
+		assert.Nil(t, err)
+		assert.True(t, found)
+		assert.Equal(t, profile{Name: "alice", Age: 30}, got)
+
+		found, err = redisClient.GetObject(ctx, "test_object_missing", &got)
+		assert.Nil(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("custom codec", func(t *testing.T) {
+		base, cleanup, err := NewTestClient()
+		if err != nil {
+			t.Fatalf("start test redis: %v", err)
+		}
+		defer cleanup()
+		codec := &prefixCodec{}
+		redisClient := NewRedisClient(base.client, WithCodec(codec))
+
+		assert.Nil(t, redisClient.SetObject(ctx, "test_object", profile{Name: "bob", Age: 41}, time.Minute))
+		raw, err := redisClient.Get(ctx, "test_object")
+		assert.Nil(t, err)
+		assert.Equal(t, `fake:{"name":"bob","age":41}`, raw)
+
+		var got profile
+		found, err := redisClient.GetObject(ctx, "test_object", &got)
+		assert.Nil(t, err)
+		assert.True(t, found)
+		assert.Equal(t, profile{Name: "bob", Age: 41}, got)
+		assert.Equal(t, 1, codec.marshals)
+		assert.Equal(t, 1, codec.unmarshals)
+	})
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// blockingCommands 自身携带阻塞时长的命令，不套用默认超时，由 go-redis 按阻塞时长放宽读超时
+var blockingCommands = map[string]struct{}{
+	"blpop": {}, "brpop": {}, "blmove": {}, "brpoplpush": {}, "blmpop": {},
+	"bzpopmin": {}, "bzpopmax": {}, "bzmpop": {},
+	"xread": {}, "xreadgroup": {}, "wait": {},
+}
+
+// WithCommandTimeout 为每条命令（含管道）设置默认超时，防止未设置截止时间的请求被卡死的连接或慢命令长时间阻塞。
+// 每次调用以 context.WithTimeout(ctx, timeout) 派生 context，调用方 context 已有更早的截止时间时以调用方为准；
+// 钩子注册在共享连接池的客户端副本上，并在副本上开启 ContextTimeoutEnabled 使截止时间作用于网络读写，
+// 只作用于当前 RedisClient，不影响共用底层客户端的其他组件，重复设置时以最短的超时为准。
+// BLPOP/BZPOPMIN/XREAD 等阻塞命令不套用默认超时，仍受调用方 context 控制
+func WithCommandTimeout(timeout time.Duration) RedisClientOption {
+	return func(r *RedisClient) {
+		if timeout <= 0 {
+			return
+		}
+		r.client = r.scopedClient()
+		r.client.Options().ContextTimeoutEnabled = true
+		r.client.AddHook(commandTimeoutHook{timeout: timeout})
+	}
+}
+
+// commandTimeoutHook 为命令派生带默认超时的 context
+type commandTimeoutHook struct {
+	timeout time.Duration
+}
+
+// withTimeout 调用方 context 没有更早的截止时间时派生带超时的 context
+func (hook commandTimeoutHook) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= hook.timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, hook.timeout)
+}
+
+// DialHook 建连沿用连接池自身的超时
+func (commandTimeoutHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 为单条非阻塞命令设置默认超时
+func (hook commandTimeoutHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if _, blocking := blockingCommands[cmd.Name()]; blocking {
+			return next(ctx, cmd)
+		}
+		ctx, cancel := hook.withTimeout(ctx)
+		defer cancel()
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook 为整个管道设置默认超时
+func (hook commandTimeoutHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, cancel := hook.withTimeout(ctx)
+		defer cancel()
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUnresponsiveRedis 启动只接受连接不回复任何数据的服务，模拟卡死的 Redis；客户端本身不设置读超时
+func newUnresponsiveRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), ReadTimeout: -1, MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// assertTimeoutError 断言错误为超时，超时通过 context 截止时间作用于连接读写
+func assertTimeoutError(t *testing.T, err error) {
+	t.Helper()
+	var netErr net.Error
+	assert.True(t, errors.As(err, &netErr) && netErr.Timeout(), "expected timeout error, got %v", err)
+}
+
+// TestWithCommandTimeout 验证 Redis 无响应时命令在配置的超时后返回，无需开启 ContextTimeoutEnabled，且不影响共用的底层客户端
+func TestWithCommandTimeout(t *testing.T) {
+	client := newUnresponsiveRedis(t)
+	redisClient := NewRedisClient(client, WithCommandTimeout(time.Minute), WithCommandTimeout(200*time.Millisecond))
+	assert.False(t, client.Options().ContextTimeoutEnabled, "shared client should keep its own options")
+
+	start := time.Now()
+	_, err := redisClient.Get(context.Background(), "test_command_timeout")
+	elapsed := time.Since(start)
+	assertTimeoutError(t, err)
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	assert.Less(t, elapsed, time.Second, "Get should be bounded by the command timeout")
+
+	start = time.Now()
+	err = redisClient.SetMany(context.Background(), map[string]Item{"test_command_timeout": {Value: "v"}})
+	assertTimeoutError(t, err)
+	assert.Less(t, time.Since(start), time.Second, "pipeline should be bounded by the command timeout")
+}
+
+// TestWithCommandTimeoutCallerDeadline 验证调用方更短的截止时间优先，无需开启 ContextTimeoutEnabled
+func TestWithCommandTimeoutCallerDeadline(t *testing.T) {
+	redisClient := NewRedisClient(newUnresponsiveRedis(t), WithCommandTimeout(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := redisClient.Get(ctx, "test_command_timeout")
+	assertTimeoutError(t, err)
+	assert.Less(t, time.Since(start), 200*time.Millisecond, "caller deadline should win when shorter")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	err = redisClient.SetMany(ctx, map[string]Item{"test_command_timeout": {Value: "v"}})
+	assertTimeoutError(t, err)
+	assert.Less(t, time.Since(start), 200*time.Millisecond, "caller deadline should win for pipelines")
+}
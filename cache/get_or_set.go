@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultGetOrSetLockTimeout = 10 * time.Second
+
+// GetOrSetLocked 读取缓存，未命中时在分布式锁保护下执行 loader 并写入缓存，
+// 集群内同一 key 同时只有一个实例执行 loader，其余实例等待锁释放后直接读取新写入的值；
+// 锁名为 key+":lock"，等待时长由 ctx 控制，持锁实例 loader 失败后由下一个获得锁的实例重新加载
+func (r *RedisClient) GetOrSetLocked(ctx context.Context, key string, ttl time.Duration, loader func() (string, error)) (string, error) {
+	lock := NewRedisLock(r.client, key+":lock", defaultGetOrSetLockTimeout)
+	for attempt := 1; ; attempt++ {
+		val, found, err := r.GetExists(ctx, key)
+		if err != nil || found {
+			return val, err
+		}
+		err = lock.Run(ctx, func(ctx context.Context) error {
+			// 获得锁后再次检查，其他实例可能已在等待期间写入
+			cached, found, err := r.GetExists(ctx, key)
+			if err != nil || found {
+				val = cached
+				return err
+			}
+			loaded, err := loader()
+			if err != nil {
+				return fmt.Errorf("cache: get or set %q: load: %w", r.maskKey(key), err)
+			}
+			if err := r.Set(ctx, key, loaded, ttl); err != nil {
+				return err
+			}
+			val = loaded
+			return nil
+		})
+		if err == nil {
+			return val, nil
+		}
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return "", err
+		}
+		timer := time.NewTimer(lock.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", fmt.Errorf("cache: get or set %q: %w", r.maskKey(key), ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetOrSetLockedAcrossClients 验证两个独立客户端同时读取冷 key 时 loader 总共只执行一次
+func TestGetOrSetLockedAcrossClients(t *testing.T) {
+	firstClient := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	secondClient := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, firstClient)
+	ctx := context.Background()
+	key := "test_get_or_set_locked"
+	_ = firstClient.Del(ctx, key, key+":lock").Err()
+	defer func() {
+		_ = firstClient.Del(ctx, key, key+":lock").Err()
+	}()
+
+	var loads atomic.Int32
+	loader := func() (string, error) {
+		loads.Add(1)
+		time.Sleep(200 * time.Millisecond) // 模拟耗时的回源计算
+		return "computed", nil
+	}
+
+	clients := []*RedisClient{NewRedisClient(firstClient), NewRedisClient(secondClient)}
+	results := make([]string, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *RedisClient) {
+			defer wg.Done()
+			val, err := client.GetOrSetLocked(ctx, key, time.Minute, loader)
+			assert.Nil(t, err)
+			results[i] = val
+		}(i, client)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load(), "loader should run once across all clients")
+	assert.Equal(t, []string{"computed", "computed"}, results)
+
+	val, err := clients[0].GetOrSetLocked(ctx, key, time.Minute, loader)
+	assert.Nil(t, err)
+	assert.Equal(t, "computed", val)
+	assert.Equal(t, int32(1), loads.Load(), "cached value should not trigger loader")
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedStruct 结构体类型或字段类型不支持与 hash 互相映射
+var ErrUnsupportedStruct = errors.New("cache: unsupported struct for hash mapping")
+
+// HSetStruct 将结构体的导出字段写入 hash，字段名取 `redis:"field"` 标签，未设置时使用小写字段名，标签为 "-" 时跳过
+// 仅支持 string/bool/整数/浮点字段，嵌套结构体、指针、切片等类型不在支持范围内
+func HSetStruct[T any](ctx context.Context, r *RedisClient, key string, v T) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("cache: hset struct %q: %w: %s", r.maskKey(key), ErrUnsupportedStruct, value.Type())
+	}
+	fields := structHashFields(value.Type())
+	values := make([]interface{}, 0, len(fields)*2)
+	for _, field := range fields {
+		raw, err := formatHashField(value.Field(field.index))
+		if err != nil {
+			return fmt.Errorf("cache: hset struct %q field %q: %w", r.maskKey(key), field.name, err)
+		}
+		values = append(values, field.name, raw)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if err := r.client.HSet(ctx, key, values...).Err(); err != nil {
+		return r.errorf("cache: hset struct %q: %w", r.maskKey(key), err)
+	}
+	return nil
+}
+
+// HGetStruct 读取 hash 并映射到结构体，key 不存在时返回 found=false，hash 中缺失的字段保持零值
+// 字段映射规则与 HSetStruct 一致
+func HGetStruct[T any](ctx context.Context, r *RedisClient, key string) (T, bool, error) {
+	var result T
+	value := reflect.ValueOf(&result).Elem()
+	if value.Kind() != reflect.Struct {
+		return result, false, fmt.Errorf("cache: hget struct %q: %w: %s", r.maskKey(key), ErrUnsupportedStruct, value.Type())
+	}
+	hash, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return result, false, r.errorf("cache: hget struct %q: %w", r.maskKey(key), err)
+	}
+	if len(hash) == 0 {
+		return result, false, nil
+	}
+	for _, field := range structHashFields(value.Type()) {
+		raw, ok := hash[field.name]
+		if !ok {
+			continue
+		}
+		if err := parseHashField(value.Field(field.index), raw); err != nil {
+			return result, false, fmt.Errorf("cache: hget struct %q field %q: %w", r.maskKey(key), field.name, err)
+		}
+	}
+	return result, true, nil
+}
+
+// hashField 结构体字段与 hash 字段的映射
+type hashField struct {
+	index int
+	name  string
+}
+
+// structHashFields 解析结构体导出字段对应的 hash 字段名
+func structHashFields(structType reflect.Type) []hashField {
+	fields := make([]hashField, 0, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("redis")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields = append(fields, hashField{index: i, name: name})
+	}
+	return fields
+}
+
+// formatHashField 将字段值格式化为 hash 字段值
+func formatHashField(value reflect.Value) (string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedStruct, value.Type())
+	}
+}
+
+// parseHashField 将 hash 字段值解析到结构体字段
+func parseHashField(value reflect.Value, raw string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedStruct, value.Type())
+	}
+	return nil
+}
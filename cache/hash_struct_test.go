@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type hashStructUser struct {
+	Name    string `redis:"user_name"`
+	Age     int
+	Active  bool
+	Score   float64
+	Ignored string `redis:"-"`
+	private string
+}
+
+// TestHashStructRoundTrip 验证结构体经 hash 往返后字段一致，并遵循标签与小写字段名规则
+func TestHashStructRoundTrip(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	key := "test_hash_struct_user"
+	defer func() {
+		_ = redisClient.Del(ctx, key)
+	}()
+
+	user := hashStructUser{Name: "alice", Age: 30, Active: true, Score: 9.5, Ignored: "skip", private: "skip"}
+	assert.Nil(t, HSetStruct(ctx, redisClient, key, user), "Should not return error while writing struct")
+
+	fields, err := redisClient.HGetAll(ctx, key)
+	assert.Nil(t, err, "Should not return error while reading hash")
+	assert.Equal(t, map[string]string{"user_name": "alice", "age": "30", "active": "true", "score": "9.5"}, fields)
+
+	got, found, err := HGetStruct[hashStructUser](ctx, redisClient, key)
+	assert.Nil(t, err, "Should not return error while reading struct")
+	assert.True(t, found, "The hash should exist")
+	assert.Equal(t, hashStructUser{Name: "alice", Age: 30, Active: true, Score: 9.5}, got)
+
+	_, found, err = HGetStruct[hashStructUser](ctx, redisClient, "test_hash_struct_missing")
+	assert.Nil(t, err, "Missing hash should not return error")
+	assert.False(t, found, "Missing hash should report not found")
+
+	type nested struct{ Inner hashStructUser }
+	err = HSetStruct(ctx, redisClient, key, nested{})
+	assert.ErrorIs(t, err, ErrUnsupportedStruct, "Nested structs are not supported")
+}
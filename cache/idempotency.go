@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// idempotencyPendingPrefix 请求处理中的占位值前缀，后接 Begin 生成的处理权 token
+const idempotencyPendingPrefix = "\x00apc:idempotency:pending:"
+
+// ErrIdempotencyInProgress 相同幂等键的请求仍在处理中，调用方可稍后重试
+var ErrIdempotencyInProgress = errors.New("cache: idempotent request in progress")
+
+// ErrIdempotencyNotStarted 幂等键不存在或已过期，Complete 前需先 Begin
+var ErrIdempotencyNotStarted = errors.New("cache: idempotent request not started")
+
+// ErrIdempotencyNotOwner 幂等键已被其他请求重新抢占或已完成，token 不再持有处理权
+var ErrIdempotencyNotOwner = errors.New("cache: idempotent request not owned")
+
+// Idempotency 基于 Redis 的幂等键助手，保证同一请求至多处理一次：
+// Begin 通过 SETNX 写入携带 token 的占位值抢占处理权，Complete 凭 token 保存最终结果，重复请求直接返回已保存的结果
+type Idempotency struct {
+	client *RedisClient
+}
+
+// NewIdempotency 创建幂等键助手
+func NewIdempotency(client *RedisClient) *Idempotency {
+	return &Idempotency{client: client}
+}
+
+// Begin 开始处理幂等请求，首次调用返回 alreadyDone=false 与处理权 token，调用方执行业务后需凭 token 调用 Complete 或 Abort；
+// 重复调用返回 alreadyDone=true 与已保存的结果，首个请求尚未完成时返回 ErrIdempotencyInProgress
+func (idempotency *Idempotency) Begin(ctx context.Context, key string, ttl time.Duration) (alreadyDone bool, stored, token string, err error) {
+	token = uuid.New().String()
+	acquired, err := idempotency.client.client.SetNX(ctx, key, idempotencyPendingPrefix+token, ttl).Result()
+	if err != nil {
+		return false, "", "", idempotency.client.errorf("cache: idempotency begin %q: %w", idempotency.client.maskKey(key), err)
+	}
+	if acquired {
+		return false, "", token, nil
+	}
+	stored, err = idempotency.client.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		// 占位值恰好过期，重新抢占
+		return idempotency.Begin(ctx, key, ttl)
+	}
+	if err != nil {
+		return false, "", "", idempotency.client.errorf("cache: idempotency begin %q: %w", idempotency.client.maskKey(key), err)
+	}
+	if strings.HasPrefix(stored, idempotencyPendingPrefix) {
+		return true, "", "", fmt.Errorf("cache: idempotency begin %q: %w", idempotency.client.maskKey(key), ErrIdempotencyInProgress)
+	}
+	return true, stored, "", nil
+}
+
+// Complete 保存请求的最终结果，保留 Begin 时设置的过期时间；
+// 仅当占位值仍属于 token 时写入，占位值过期后被其他请求重新抢占时返回 ErrIdempotencyNotOwner，避免覆盖其处理状态
+func (idempotency *Idempotency) Complete(ctx context.Context, key, token, result string) error {
+	luaScript := `
+		local current = redis.call("GET", KEYS[1])
+		if not current then
+			return 0
+		end
+		if current ~= ARGV[1] then
+			return -1
+		end
+		redis.call("SET", KEYS[1], ARGV[2], "KEEPTTL")
+		return 1
+	`
+	updated, err := idempotency.client.client.Eval(ctx, luaScript, []string{key}, idempotencyPendingPrefix+token, result).Int64()
+	if err != nil {
+		return idempotency.client.errorf("cache: idempotency complete %q: %w", idempotency.client.maskKey(key), err)
+	}
+	switch updated {
+	case 0:
+		return fmt.Errorf("cache: idempotency complete %q: %w", idempotency.client.maskKey(key), ErrIdempotencyNotStarted)
+	case -1:
+		return fmt.Errorf("cache: idempotency complete %q: %w", idempotency.client.maskKey(key), ErrIdempotencyNotOwner)
+	}
+	return nil
+}
+
+// Abort 放弃 token 持有的处理中请求并删除占位值，允许后续请求重新执行，已完成的结果与其他请求的占位值不受影响
+func (idempotency *Idempotency) Abort(ctx context.Context, key, token string) error {
+	luaScript := `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		end
+		return 0
+	`
+	if err := idempotency.client.client.Eval(ctx, luaScript, []string{key}, idempotencyPendingPrefix+token).Err(); err != nil {
+		return idempotency.client.errorf("cache: idempotency abort %q: %w", idempotency.client.maskKey(key), err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdempotencyConcurrentRequests 验证相同幂等键的并发请求只执行一次，另一个请求拿到缓存结果
+func TestIdempotencyConcurrentRequests(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	idempotency := NewIdempotency(NewRedisClient(client))
+	ctx := context.Background()
+	key := "test_idempotency_payment"
+	_ = client.Del(ctx, key).Err()
+	defer func() {
+		_ = client.Del(ctx, key).Err()
+	}()
+
+	var executions atomic.Int32
+	handle := func() (string, error) {
+		for {
+			done, stored, token, err := idempotency.Begin(ctx, key, time.Minute)
+			if errors.Is(err, ErrIdempotencyInProgress) {
+				time.Sleep(20 * time.Millisecond)
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			if done {
+				return stored, nil
+			}
+			executions.Add(1)
+			time.Sleep(100 * time.Millisecond) // 模拟支付处理
+			result := `{"status":"paid"}`
+			return result, idempotency.Complete(ctx, key, token, result)
+		}
+	}
+
+	results := make([]string, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := handle()
+			assert.Nil(t, err)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), executions.Load(), "payment should execute exactly once")
+	assert.Equal(t, `{"status":"paid"}`, results[0])
+	assert.Equal(t, results[0], results[1])
+
+	ttl, err := client.TTL(ctx, key).Result()
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0, "Complete should keep the original ttl")
+}
+
+// TestIdempotencyAbort 验证 Abort 后相同幂等键可重新执行，未 Begin 的 Complete 返回错误
+func TestIdempotencyAbort(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	idempotency := NewIdempotency(NewRedisClient(client))
+	ctx := context.Background()
+	key := "test_idempotency_abort"
+	_ = client.Del(ctx, key).Err()
+	defer func() {
+		_ = client.Del(ctx, key).Err()
+	}()
+
+	done, _, token, err := idempotency.Begin(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, done)
+	assert.Nil(t, idempotency.Abort(ctx, key, token))
+
+	done, _, _, err = idempotency.Begin(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, done, "aborted key should be claimable again")
+
+	assert.ErrorIs(t, idempotency.Complete(ctx, "test_idempotency_missing", token, "x"), ErrIdempotencyNotStarted)
+}
+
+// TestIdempotencyExpiredBeforeComplete 验证占位值过期后被其他请求抢占时，原请求的 Complete 与 Abort 不会覆盖或删除新的占位值
+func TestIdempotencyExpiredBeforeComplete(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	idempotency := NewIdempotency(NewRedisClient(client))
+	ctx := context.Background()
+	key := "test_idempotency_expired"
+
+	_, _, first, err := idempotency.Begin(ctx, key, time.Second)
+	assert.Nil(t, err)
+	server.FastForward(2 * time.Second)
+	done, _, second, err := idempotency.Begin(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, done, "expired key should be claimable again")
+
+	assert.ErrorIs(t, idempotency.Complete(ctx, key, first, "first"), ErrIdempotencyNotOwner)
+	assert.Nil(t, idempotency.Abort(ctx, key, first))
+	_, _, _, err = idempotency.Begin(ctx, key, time.Minute)
+	assert.ErrorIs(t, err, ErrIdempotencyInProgress, "stale owner should not complete or abort the new claim")
+
+	assert.Nil(t, idempotency.Complete(ctx, key, second, "second"))
+	done, stored, _, err := idempotency.Begin(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "second", stored)
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache 常用字符串键值操作的抽象，业务代码依赖该接口即可在单元测试中替换为 MemoryCache
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	GetExists(ctx context.Context, key string) (string, bool, error)
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+	Set(ctx context.Context, key string, val any, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, val any, ttl time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+	Exists(ctx context.Context, keys ...string) (int64, error)
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrBy(ctx context.Context, key string, step int64) (int64, error)
+	Close() error
+}
+
+var (
+	_ Cache = (*RedisClient)(nil)
+	_ Cache = (*MemoryCache)(nil)
+)
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// versionedEnvelope 带结构版本号的 JSON 外层，形如 {"v":2,"data":{...}}
+type versionedEnvelope struct {
+	Version int             `json:"v"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// SetJSONVersioned 将 v 编码为 JSON 并附带结构版本号写入 key，结构变更时递增 version 使旧数据失效
+func SetJSONVersioned[T any](ctx context.Context, r *RedisClient, key string, v T, version int, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache: set json versioned %q: %w", r.maskKey(key), err)
+	}
+	payload, err := json.Marshal(versionedEnvelope{Version: version, Data: data})
+	if err != nil {
+		return fmt.Errorf("cache: set json versioned %q: %w", r.maskKey(key), err)
+	}
+	if err := r.client.Set(ctx, key, payload, ttl).Err(); err != nil {
+		return r.errorf("cache: set json versioned %q: %w", r.maskKey(key), err)
+	}
+	return nil
+}
+
+// GetJSONVersioned 读取 SetJSONVersioned 写入的值，key 不存在、版本号与 expectedVersion 不一致或不是带版本的数据时
+// 均按未命中处理（found 为 false），调用方重新计算并写入即可完成迁移
+func GetJSONVersioned[T any](ctx context.Context, r *RedisClient, key string, expectedVersion int) (T, bool, error) {
+	var zero T
+	raw, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, r.errorf("cache: get json versioned %q: %w", r.maskKey(key), err)
+	}
+	var envelope versionedEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil || envelope.Version != expectedVersion || envelope.Data == nil {
+		return zero, false, nil
+	}
+	var decoded T
+	if err := json.Unmarshal(envelope.Data, &decoded); err != nil {
+		return zero, false, fmt.Errorf("cache: get json versioned %q: %w", r.maskKey(key), err)
+	}
+	return decoded, true, nil
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJSONVersioned 验证版本一致时命中，版本不一致或旧的无版本数据按未命中处理
+func TestJSONVersioned(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	type profileV1 struct {
+		Name string `json:"name"`
+	}
+	key := "test_json_versioned"
+
+	assert.Nil(t, SetJSONVersioned(ctx, redisClient, key, profileV1{Name: "alice"}, 1, time.Minute))
+	got, found, err := GetJSONVersioned[profileV1](ctx, redisClient, key, 1)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, profileV1{Name: "alice"}, got)
+
+	_, found, err = GetJSONVersioned[profileV1](ctx, redisClient, key, 2)
+	assert.Nil(t, err)
+	assert.False(t, found, "version mismatch should be treated as a miss")
+
+	assert.Nil(t, redisClient.Set(ctx, key, `{"name":"legacy"}`, time.Minute))
+	_, found, err = GetJSONVersioned[profileV1](ctx, redisClient, key, 1)
+	assert.Nil(t, err)
+	assert.False(t, found, "unversioned data should be treated as a miss")
+
+	_, found, err = GetJSONVersioned[profileV1](ctx, redisClient, "test_json_versioned_missing", 1)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,30 @@
+package cache
+
+// WithKeyMasker 设置错误信息中 key 的脱敏函数（如哈希或截断），避免 session token 等敏感 key 随错误进入日志；
+// 同样作用于基于该 RedisClient 的组件（WindowCounter、ReliableQueue、Barrier、RWLock、Idempotency 等）；
+// 默认原样输出，仅影响错误字符串，不改变实际读写的 key
+func WithKeyMasker(masker func(key string) string) RedisClientOption {
+	return func(r *RedisClient) {
+		r.keyMasker = masker
+	}
+}
+
+// maskKey 返回写入错误信息的 key
+func (r *RedisClient) maskKey(key string) string {
+	if r.keyMasker == nil {
+		return key
+	}
+	return r.keyMasker(key)
+}
+
+// maskKeys 返回写入错误信息的 key 列表
+func (r *RedisClient) maskKeys(keys []string) []string {
+	if r.keyMasker == nil {
+		return keys
+	}
+	masked := make([]string, len(keys))
+	for i, key := range keys {
+		masked[i] = r.keyMasker(key)
+	}
+	return masked
+}
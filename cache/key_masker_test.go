@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedisClientKeyMasker 验证配置脱敏函数后错误信息只包含脱敏后的 key
+func TestRedisClientKeyMasker(t *testing.T) {
+	base, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	masker := func(key string) string {
+		sum := sha256.Sum256([]byte(key))
+		return "sha256:" + hex.EncodeToString(sum[:4])
+	}
+	redisClient := NewRedisClient(base.client, WithKeyMasker(masker))
+	ctx := context.Background()
+	key := "session:secret-token"
+
+	// 对 list 执行 GET 触发 WRONGTYPE 错误
+	_, err = redisClient.LPush(ctx, key, "v")
+	assert.Nil(t, err)
+	_, err = redisClient.Get(ctx, key)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), masker(key))
+	assert.NotContains(t, err.Error(), "secret-token")
+
+	_, err = NewRedisClient(base.client).Get(ctx, key)
+	assert.Contains(t, err.Error(), key, "default masker should keep the raw key")
+}
+
+// TestKeyMaskerCoversComponents 验证基于 RedisClient 的组件返回的错误同样只包含脱敏后的 key
+func TestKeyMaskerCoversComponents(t *testing.T) {
+	base, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	redisClient := NewRedisClient(base.client, WithKeyMasker(func(string) string { return "masked" }))
+	// 关闭服务使所有命令失败
+	cleanup()
+	ctx := context.Background()
+	name := "session:secret-token"
+
+	_, windowErr := NewWindowCounter(redisClient).Incr(ctx, name, time.Second)
+	queueErr := NewReliableQueue(redisClient, name, "consumer").Push(ctx, "v")
+	barrierErr := NewBarrier(redisClient, time.Minute).InitBarrier(ctx, name, 1)
+	_, rwlockErr := NewRWLock(redisClient, name, time.Second).TryRLock(ctx)
+	_, sequenceErr := NewSequence(redisClient).Next(ctx, name)
+	_, sequenceBatchErr := NewSequence(redisClient).NextBatch(ctx, name, 0)
+	_, tagErr := redisClient.InvalidateTag(ctx, name)
+	for _, err := range []error{windowErr, queueErr, barrierErr, rwlockErr, sequenceErr, sequenceBatchErr, tagErr} {
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "masked")
+		assert.NotContains(t, err.Error(), "secret-token")
+	}
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrWaitKeyTimeout 等待 key 写入超时
+var ErrWaitKeyTimeout = errors.New("cache: wait for key timeout")
+
+// WaitForKey 阻塞等待 key 被写入并返回其值，key 已存在时立即返回，超时返回 ErrWaitKeyTimeout
+// 依赖 Redis 键空间通知，服务端需开启 notify-keyspace-events 且至少包含 K 与 $（如 "K$" 或 "KEA"），
+// 未开启时只能在超时前 key 已存在的情况下返回
+func (r *RedisClient) WaitForKey(ctx context.Context, key string, timeout time.Duration) (string, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	channel := fmt.Sprintf("__keyspace@%d__:%s", r.client.Options().DB, key)
+	pubsub := r.client.Subscribe(waitCtx, channel)
+	defer pubsub.Close()
+	// 等待订阅确认后再检查 key，避免订阅建立前的写入被遗漏
+	if _, err := pubsub.Receive(waitCtx); err != nil {
+		return "", r.waitForKeyError(ctx, waitCtx, key, err)
+	}
+	messages := pubsub.Channel()
+	for {
+		val, err := r.client.Get(waitCtx, key).Result()
+		if err == nil {
+			return val, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return "", r.waitForKeyError(ctx, waitCtx, key, err)
+		}
+		select {
+		case <-waitCtx.Done():
+			return "", r.waitForKeyError(ctx, waitCtx, key, waitCtx.Err())
+		case <-messages:
+			// 任意写事件都重新读取，兼容 SET/SETEX/MSET 等命令
+		}
+	}
+}
+
+// waitForKeyError 区分调用方取消、等待超时与 Redis 错误
+func (r *RedisClient) waitForKeyError(ctx, waitCtx context.Context, key string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("cache: wait for key %q: %w", r.maskKey(key), ctx.Err())
+	}
+	if waitCtx.Err() != nil {
+		return fmt.Errorf("cache: wait for key %q: %w", r.maskKey(key), ErrWaitKeyTimeout)
+	}
+	return r.errorf("cache: wait for key %q: %w", r.maskKey(key), err)
+}
+
+// SubscribeInvalidation 订阅 prefix 开头的 key 的键空间通知，其他实例写入、删除或 key 过期时调用 evict 淘汰进程内缓存，
+// 用于让本地（L1）缓存在集群内尽力保持一致；服务端需开启 notify-keyspace-events（建议 "KA"，至少包含 K 与对应命令类型），
+// 断线重连期间的通知会丢失，本地缓存仍应设置较短的过期时间兜底；返回的 stop 关闭订阅并等待回调退出
+func (r *RedisClient) SubscribeInvalidation(ctx context.Context, prefix string, evict func(key string)) (stop func() error, err error) {
+	channelPrefix := fmt.Sprintf("__keyspace@%d__:", r.client.Options().DB)
+	pubsub := r.client.PSubscribe(ctx, channelPrefix+escapeGlob(prefix)+"*")
+	// 等待订阅确认，确保返回后发生的写入都能收到通知
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, r.errorf("cache: subscribe invalidation %q: %w", r.maskKey(prefix), err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range pubsub.Channel() {
+			evict(strings.TrimPrefix(msg.Channel, channelPrefix))
+		}
+	}()
+	var once sync.Once
+	return func() error {
+		var closeErr error
+		once.Do(func() {
+			closeErr = pubsub.Close()
+			<-done
+		})
+		return closeErr
+	}, nil
+}
+
+// escapeGlob 转义 glob 特殊字符，使前缀按字面匹配
+func escapeGlob(pattern string) string {
+	var builder strings.Builder
+	for _, ch := range pattern {
+		switch ch {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(ch)
+	}
+	return builder.String()
+}
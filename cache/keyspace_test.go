@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// enableKeyspaceEvents 开启键空间通知，测试结束后恢复原配置，不支持时跳过测试
+func enableKeyspaceEvents(t *testing.T, client *redis.Client) {
+	t.Helper()
+	ctx := context.Background()
+	previous, err := client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		t.Skipf("redis does not support keyspace notifications: %v", err)
+	}
+	if err := client.ConfigSet(ctx, "notify-keyspace-events", "K$").Err(); err != nil {
+		t.Skipf("redis does not support keyspace notifications: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.ConfigSet(ctx, "notify-keyspace-events", previous["notify-keyspace-events"]).Err()
+	})
+}
+
+// TestRedisClientWaitForKey 验证 key 延迟写入后 WaitForKey 立即返回其值，未写入时超时
+func TestRedisClientWaitForKey(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	enableKeyspaceEvents(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	key := "test_wait_for_key"
+	_ = redisClient.Del(ctx, key)
+	defer func() {
+		_ = redisClient.Del(ctx, key)
+	}()
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = redisClient.Set(ctx, key, "ready", time.Minute)
+	}()
+
+	start := time.Now()
+	val, err := redisClient.WaitForKey(ctx, key, 3*time.Second)
+	assert.Nil(t, err, "Should not return error while waiting for key")
+	assert.Equal(t, "ready", val, "Should return the value written by another client")
+	assert.Less(t, time.Since(start), 2*time.Second, "Should return as soon as the key appears")
+
+	_, err = redisClient.WaitForKey(ctx, "test_wait_for_key_missing", 100*time.Millisecond)
+	assert.ErrorIs(t, err, ErrWaitKeyTimeout, "Should time out when the key never appears")
+}
+
+// TestRedisClientSubscribeInvalidation 验证一个实例写入共享前缀的 key 后，另一实例订阅到通知并淘汰本地缓存
+func TestRedisClientSubscribeInvalidation(t *testing.T) {
+	writerClient := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, writerClient)
+	enableKeyspaceEvents(t, writerClient)
+	readerClient := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer readerClient.Close()
+	writer := NewRedisClient(writerClient)
+	reader := NewRedisClient(readerClient)
+	ctx := context.Background()
+	prefix := "test_invalidation:"
+	key := prefix + "user:1"
+	defer func() {
+		_ = writer.Del(ctx, key)
+	}()
+
+	var mu sync.Mutex
+	localCache := map[string]string{key: "stale", "other:user:1": "kept"}
+	evicted := make(chan string, 1)
+	stop, err := reader.SubscribeInvalidation(ctx, prefix, func(key string) {
+		mu.Lock()
+		delete(localCache, key)
+		mu.Unlock()
+		evicted <- key
+	})
+	assert.Nil(t, err)
+	defer func() {
+		assert.Nil(t, stop())
+	}()
+
+	assert.Nil(t, writer.Set(ctx, key, "fresh", time.Minute))
+	select {
+	case got := <-evicted:
+		assert.Equal(t, key, got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("local cache entry should be evicted after a write from another client")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assert.NotContains(t, localCache, key)
+	assert.Contains(t, localCache, "other:user:1", "keys outside the prefix should be kept")
+}
@@ -0,0 +1,34 @@
+package cache
+
+// LockObserver 分布式锁生命周期观察者，可接入指标或日志用于排查锁竞争
+// 回调在 Redis 操作完成后同步调用，不产生额外往返，实现方应避免阻塞
+type LockObserver interface {
+	// OnAcquireAttempt 每次尝试获取锁前调用
+	OnAcquireAttempt(name, owner string)
+	// OnAcquired 获取锁成功后调用
+	OnAcquired(name, owner string)
+	// OnAcquireFailed 获取锁失败后调用，锁被他人持有时 err 为 nil
+	OnAcquireFailed(name, owner string, err error)
+	// OnRenew 续期完成后调用，held 表示续期后是否仍持有锁
+	OnRenew(name, owner string, held bool, err error)
+	// OnRelease 释放完成后调用，released 表示锁是否被本持有者删除
+	OnRelease(name, owner string, released bool, err error)
+}
+
+// noopLockObserver 默认观察者，不做任何处理
+type noopLockObserver struct{}
+
+func (noopLockObserver) OnAcquireAttempt(string, string)       {}
+func (noopLockObserver) OnAcquired(string, string)             {}
+func (noopLockObserver) OnAcquireFailed(string, string, error) {}
+func (noopLockObserver) OnRenew(string, string, bool, error)   {}
+func (noopLockObserver) OnRelease(string, string, bool, error) {}
+
+// WithObserver 设置锁生命周期观察者，传入 nil 时不生效
+func WithObserver(observer LockObserver) RedisLockOption {
+	return func(lock *RedisLock) {
+		if observer != nil {
+			lock.observer = observer
+		}
+	}
+}
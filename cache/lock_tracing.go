@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apctracing "github.com/ethereal3x/apc/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 锁 span 的结果取值
+const (
+	lockOutcomeAcquired    = "acquired"
+	lockOutcomeNotAcquired = "not_acquired"
+	lockOutcomeReleased    = "released"
+	lockOutcomeLost        = "lost"
+	lockOutcomeError       = "error"
+)
+
+// LockTracer 分布式锁的链路埋点接口，锁只依赖该接口，可接入任意追踪实现
+type LockTracer interface {
+	// StartLockSpan 以 ctx 为父创建 span，返回携带新 span 的 context
+	StartLockSpan(ctx context.Context, name string) (context.Context, LockSpan)
+}
+
+// LockSpan 锁操作对应的 span
+type LockSpan interface {
+	// SetAttribute 设置 span 属性
+	SetAttribute(key string, value any)
+	// RecordError 记录错误并将 span 标记为失败
+	RecordError(err error)
+	// End 结束 span
+	End()
+}
+
+// WithLockTracing 为 Acquire/AcquireWithTimeout/AcquireWithToken 创建 lock.acquire span、为 Release 创建 lock.release span，
+// 记录 lock.name、lock.wait_ms 与 lock.outcome 属性；接入 OpenTelemetry 时传入 NewOtelLockTracer()，tracer 为 nil 时不创建 span
+func WithLockTracing(tracer LockTracer) RedisLockOption {
+	return func(lock *RedisLock) {
+		if tracer == nil {
+			tracer = noopLockTracer{}
+		}
+		lock.tracer = tracer
+	}
+}
+
+// startSpan 创建锁操作 span 并写入锁名
+func (lock *RedisLock) startSpan(ctx context.Context, name string) (context.Context, LockSpan) {
+	ctx, span := lock.tracer.StartLockSpan(ctx, name)
+	span.SetAttribute("lock.name", lock.lockName)
+	return ctx, span
+}
+
+// endSpan 写入等待时长与结果后结束 span
+func endSpan(span LockSpan, start time.Time, outcome string, err error) {
+	span.SetAttribute("lock.wait_ms", time.Since(start).Milliseconds())
+	span.SetAttribute("lock.outcome", outcome)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// acquireOutcome 将获取结果映射为 lock.outcome 取值
+func acquireOutcome(locked bool, err error) string {
+	switch {
+	case err != nil:
+		return lockOutcomeError
+	case locked:
+		return lockOutcomeAcquired
+	default:
+		return lockOutcomeNotAcquired
+	}
+}
+
+// noopLockTracer 默认 tracer，不创建 span
+type noopLockTracer struct{}
+
+func (noopLockTracer) StartLockSpan(ctx context.Context, _ string) (context.Context, LockSpan) {
+	return ctx, noopLockSpan{}
+}
+
+// noopLockSpan 不做任何处理的 span
+type noopLockSpan struct{}
+
+func (noopLockSpan) SetAttribute(string, any) {}
+func (noopLockSpan) RecordError(error)        {}
+func (noopLockSpan) End()                     {}
+
+// NewOtelLockTracer 返回基于 tracing 包全局 tracer 的 OpenTelemetry 实现，供 WithLockTracing 使用
+func NewOtelLockTracer() LockTracer {
+	return otelLockTracer{}
+}
+
+// otelLockTracer 基于 tracing 包的 OpenTelemetry 实现
+type otelLockTracer struct{}
+
+func (otelLockTracer) StartLockSpan(ctx context.Context, name string) (context.Context, LockSpan) {
+	ctx, span := apctracing.Start(ctx, name)
+	return ctx, otelLockSpan{ctx: ctx, span: span}
+}
+
+// otelLockSpan 包装 OpenTelemetry span
+type otelLockSpan struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+func (s otelLockSpan) SetAttribute(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+func (s otelLockSpan) RecordError(err error) {
+	apctracing.RecordError(s.ctx, err)
+}
+
+func (s otelLockSpan) End() {
+	s.span.End()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"os"
 	"sync"
 	"time"
 
@@ -11,7 +13,12 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-const defaultRedisLockTimeout = 30 * time.Second
+const (
+	defaultRedisLockTimeout = 30 * time.Second
+	defaultRetryBase        = 10 * time.Millisecond
+	defaultRetryMax         = 500 * time.Millisecond
+	defaultLockOpTimeout    = 3 * time.Second
+)
 
 // ErrLockNotAcquired 获取锁失败
 var ErrLockNotAcquired = errors.New("cache: could not acquire lock")
@@ -19,6 +26,9 @@ var ErrLockNotAcquired = errors.New("cache: could not acquire lock")
 // ErrLockLost 续租时发现锁已丢失
 var ErrLockLost = errors.New("cache: lock lost during renewal")
 
+// ErrLockMaxLifetime 持锁时间达到 WithMaxLifetime 上限，续租已停止
+var ErrLockMaxLifetime = errors.New("cache: lock max lifetime exceeded")
+
 // RedisLock 基于 Redis 的分布式锁实现，提供 Lease 模型的 Run 方法
 type RedisLock struct {
 	client    *redis.Client
@@ -26,26 +36,141 @@ type RedisLock struct {
 	lockValue string
 	timeout   time.Duration
 
+	retryBase time.Duration
+	retryMax  time.Duration
+	opTimeout time.Duration
+	observer  LockObserver
+	tracer    LockTracer
+
+	maxLifetime time.Duration
+	cancelTask  bool
+
 	mu          sync.Mutex
 	keepAlive   bool
 	keepAliveCh chan struct{}
+	token       int64
+}
+
+// RedisLockOption 分布式锁配置选项
+type RedisLockOption func(*RedisLock)
+
+// WithLockOwner 指定持有者标识作为锁值，需保证不同持有者之间唯一
+func WithLockOwner(owner string) RedisLockOption {
+	return func(lock *RedisLock) {
+		if owner != "" {
+			lock.lockValue = owner
+		}
+	}
+}
+
+// WithRetryBackoff 设置 AcquireWithTimeout 的退避参数，base 为初始间隔，max 为单次等待上限
+func WithRetryBackoff(base, max time.Duration) RedisLockOption {
+	return func(lock *RedisLock) {
+		if base > 0 {
+			lock.retryBase = base
+		}
+		if max > 0 {
+			lock.retryMax = max
+		}
+	}
+}
+
+// WithOperationTimeout 设置单次 Redis 操作的超时时间（默认 3s），调用方 context 无截止时间时防止 Redis 无响应导致长时间阻塞
+// go-redis 仅在 Options.ContextTimeoutEnabled 为 true 时将 context 截止时间应用到网络读写，否则以 ReadTimeout/WriteTimeout 为准
+func WithOperationTimeout(timeout time.Duration) RedisLockOption {
+	return func(lock *RedisLock) {
+		if timeout > 0 {
+			lock.opTimeout = timeout
+		}
+	}
 }
 
-// NewRedisLock 创建 Redis 分布式锁实例，lockValue 自动生成唯一标识防止误释放
-func NewRedisLock(client *redis.Client, lockName string, timeout time.Duration) *RedisLock {
+// WithMaxLifetime 设置续租的最长持续时间，到期后停止续租让锁按 TTL 自然过期，避免卡死的任务无限期持锁
+// cancelTask 为 true 时同时取消 Run 传给业务的 context，Run 返回 ErrLockMaxLifetime
+func WithMaxLifetime(maxLifetime time.Duration, cancelTask bool) RedisLockOption {
+	return func(lock *RedisLock) {
+		if maxLifetime > 0 {
+			lock.maxLifetime = maxLifetime
+			lock.cancelTask = cancelTask
+		}
+	}
+}
+
+// NewRedisLock 创建 Redis 分布式锁实例，lockValue 默认由 hostname+pid+随机串组成，既防止误释放也用于排查持有者
+func NewRedisLock(client *redis.Client, lockName string, timeout time.Duration, opts ...RedisLockOption) *RedisLock {
 	if timeout <= 0 {
 		timeout = defaultRedisLockTimeout
 	}
-	return &RedisLock{
+	lock := &RedisLock{
 		client:    client,
 		lockName:  lockName,
-		lockValue: uuid.New().String(),
+		lockValue: DefaultLockOwner(),
 		timeout:   timeout,
+		retryBase: defaultRetryBase,
+		retryMax:  defaultRetryMax,
+		opTimeout: defaultLockOpTimeout,
+		observer:  noopLockObserver{},
+		tracer:    noopLockTracer{},
+	}
+	for _, option := range opts {
+		option(lock)
+	}
+	if lock.retryMax < lock.retryBase {
+		lock.retryMax = lock.retryBase
 	}
+	return lock
 }
 
-// Acquire 尝试获取分布式锁，通过 context 控制超时
+// DefaultLockOwner 生成 hostname:pid:uuid 形式的持有者标识
+func DefaultLockOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.New().String())
+}
+
+// Owner 读取当前锁持有者标识，锁未被持有时返回空字符串
+func (lock *RedisLock) Owner(ctx context.Context) (string, error) {
+	ctx, cancel := lock.opContext(ctx)
+	defer cancel()
+	owner, err := lock.client.Get(ctx, lock.lockName).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cache: lock owner %q: %w", lock.lockName, err)
+	}
+	return owner, nil
+}
+
+// IsLocked 检查锁当前是否被任意持有者持有
+func (lock *RedisLock) IsLocked(ctx context.Context) (bool, error) {
+	ctx, cancel := lock.opContext(ctx)
+	defer cancel()
+	count, err := lock.client.Exists(ctx, lock.lockName).Result()
+	if err != nil {
+		return false, fmt.Errorf("cache: lock exists %q: %w", lock.lockName, err)
+	}
+	return count > 0, nil
+}
+
+// opContext 为单次 Redis 操作派生带超时的 context，调用方截止时间更早时以调用方为准
+func (lock *RedisLock) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, lock.opTimeout)
+}
+
+// Acquire 尝试获取分布式锁，通过 context 控制超时，单次操作最长等待 opTimeout
 func (lock *RedisLock) Acquire(ctx context.Context) (bool, error) {
+	ctx, span := lock.startSpan(ctx, "lock.acquire")
+	start := time.Now()
+	locked, err := lock.acquire(ctx)
+	endSpan(span, start, acquireOutcome(locked, err), err)
+	return locked, err
+}
+
+// acquire 执行一次 SET NX 获取锁
+func (lock *RedisLock) acquire(ctx context.Context) (bool, error) {
 	luaScript := `
 		if redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2], "NX") then
 			return 1
@@ -54,28 +179,146 @@ func (lock *RedisLock) Acquire(ctx context.Context) (bool, error) {
 		end
 	`
 	ttl := int64(lock.timeout / time.Millisecond)
+	ctx, cancel := lock.opContext(ctx)
+	defer cancel()
+	lock.observer.OnAcquireAttempt(lock.lockName, lock.lockValue)
 	result, err := lock.client.Eval(ctx, luaScript, []string{lock.lockName}, lock.lockValue, ttl).Result()
 	if err != nil {
-		return false, fmt.Errorf("cache: acquire lock %q: %w", lock.lockName, err)
+		err = fmt.Errorf("cache: acquire lock %q: %w", lock.lockName, err)
+		lock.observer.OnAcquireFailed(lock.lockName, lock.lockValue, err)
+		return false, err
+	}
+	if result.(int64) != 1 {
+		lock.observer.OnAcquireFailed(lock.lockName, lock.lockValue, nil)
+		return false, nil
 	}
-	return result.(int64) == 1, nil
+	lock.observer.OnAcquired(lock.lockName, lock.lockValue)
+	return true, nil
+}
+
+// AcquireWithToken 尝试获取锁，成功时在同一脚本中对伴生 key（lockName:fence）执行 INCR 得到单调递增的 fencing token
+// 下游存储应记录已见过的最大 token 并拒绝携带更小 token 的写入，防止锁过期后（如 GC 停顿）旧持有者继续写入；
+// 伴生 key 不设过期时间，集群模式下 lockName 需使用 hash tag 保证两个 key 位于同一 slot
+func (lock *RedisLock) AcquireWithToken(ctx context.Context) (int64, bool, error) {
+	ctx, span := lock.startSpan(ctx, "lock.acquire")
+	start := time.Now()
+	token, locked, err := lock.acquireWithToken(ctx)
+	endSpan(span, start, acquireOutcome(locked, err), err)
+	return token, locked, err
+}
+
+// acquireWithToken 获取锁并递增 fencing token
+func (lock *RedisLock) acquireWithToken(ctx context.Context) (int64, bool, error) {
+	luaScript := `
+		if redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2], "NX") then
+			return redis.call("INCR", KEYS[2])
+		else
+			return 0
+		end
+	`
+	ttl := int64(lock.timeout / time.Millisecond)
+	ctx, cancel := lock.opContext(ctx)
+	defer cancel()
+	lock.observer.OnAcquireAttempt(lock.lockName, lock.lockValue)
+	token, err := lock.client.Eval(ctx, luaScript, []string{lock.lockName, lock.fenceKey()}, lock.lockValue, ttl).Int64()
+	if err != nil {
+		err = fmt.Errorf("cache: acquire lock %q: %w", lock.lockName, err)
+		lock.observer.OnAcquireFailed(lock.lockName, lock.lockValue, err)
+		return 0, false, err
+	}
+	if token == 0 {
+		lock.observer.OnAcquireFailed(lock.lockName, lock.lockValue, nil)
+		return 0, false, nil
+	}
+	lock.mu.Lock()
+	lock.token = token
+	lock.mu.Unlock()
+	lock.observer.OnAcquired(lock.lockName, lock.lockValue)
+	return token, true, nil
+}
+
+// Token 返回最近一次 AcquireWithToken 成功获取的 fencing token，从未获取时为 0
+func (lock *RedisLock) Token() int64 {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	return lock.token
+}
+
+// fenceKey 返回存放 fencing token 计数器的伴生 key
+func (lock *RedisLock) fenceKey() string {
+	return lock.lockName + ":fence"
+}
+
+// AcquireWithTimeout 在 maxWait 内以指数退避加全抖动重试获取锁，超时返回 false
+// 随机化的重试间隔可避免大量竞争者在锁释放瞬间同时重试
+func (lock *RedisLock) AcquireWithTimeout(ctx context.Context, maxWait time.Duration) (bool, error) {
+	ctx, span := lock.startSpan(ctx, "lock.acquire")
+	start := time.Now()
+	locked, attempts, err := lock.acquireWithBackoff(ctx, maxWait)
+	span.SetAttribute("lock.attempts", attempts)
+	endSpan(span, start, acquireOutcome(locked, err), err)
+	return locked, err
+}
+
+// acquireWithBackoff 退避重试获取锁并返回尝试次数
+func (lock *RedisLock) acquireWithBackoff(ctx context.Context, maxWait time.Duration) (bool, int, error) {
+	deadline := time.Now().Add(maxWait)
+	for attempt := 1; ; attempt++ {
+		locked, err := lock.acquire(ctx)
+		if err != nil || locked {
+			return locked, attempt, err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false, attempt, nil
+		}
+		wait := min(lock.backoff(attempt), remaining)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, attempt, fmt.Errorf("cache: acquire lock %q: %w", lock.lockName, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff 计算第 attempt 次失败后的等待时长，取 [0, min(retryMax, retryBase*2^attempt)] 内的随机值
+func (lock *RedisLock) backoff(attempt int) time.Duration {
+	ceiling := lock.retryMax
+	if attempt < 32 {
+		if exp := lock.retryBase << attempt; exp > 0 && exp < ceiling {
+			ceiling = exp
+		}
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
 }
 
 // Release 释放分布式锁，仅当锁值匹配时才删除
 func (lock *RedisLock) Release(ctx context.Context) error {
 	lock.stopKeepAlive()
+	ctx, span := lock.startSpan(ctx, "lock.release")
+	start := time.Now()
 	locked, err := lock.release(ctx)
 	if err != nil {
+		endSpan(span, start, lockOutcomeError, err)
 		return err
 	}
 	if !locked {
+		endSpan(span, start, lockOutcomeLost, nil)
 		return fmt.Errorf("cache: release lock %q: lock already lost or value mismatch", lock.lockName)
 	}
+	endSpan(span, start, lockOutcomeReleased, nil)
 	return nil
 }
 
 // KeepAlive 启动定期续期 goroutine，重复调用不重复启动
 func (lock *RedisLock) KeepAlive() {
+	lock.KeepAliveContext(context.Background())
+}
+
+// KeepAliveContext 启动定期续期 goroutine，ctx 取消或 Release 时退出，重复调用不重复启动
+func (lock *RedisLock) KeepAliveContext(ctx context.Context) {
 	lock.mu.Lock()
 	if lock.keepAlive {
 		lock.mu.Unlock()
@@ -87,21 +330,47 @@ func (lock *RedisLock) KeepAlive() {
 	lock.mu.Unlock()
 
 	ticker := time.NewTicker(lock.timeout / 2)
+	expired, stopExpiry := lock.lifetimeTimer()
 	go func() {
 		defer ticker.Stop()
+		defer stopExpiry()
 		for {
 			select {
 			case <-ticker.C:
-				if _, err := lock.renew(context.Background()); err != nil {
+				if _, err := lock.renew(ctx); err != nil {
 					fmt.Printf("cache: keep lock %q alive failed: %v\n", lock.lockName, err)
 				}
 			case <-stopCh:
 				return
+			case <-ctx.Done():
+				lock.resetKeepAlive(stopCh)
+				return
+			case <-expired:
+				lock.resetKeepAlive(stopCh)
+				return
 			}
 		}
 	}()
 }
 
+// resetKeepAlive 续期 goroutine 自行退出时清除运行标记，允许重新启动
+func (lock *RedisLock) resetKeepAlive(stopCh chan struct{}) {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if lock.keepAliveCh == stopCh {
+		lock.keepAlive = false
+	}
+}
+
+// lifetimeTimer 返回达到最长续租时间时触发的 channel，未设置上限时返回永不触发的 nil channel
+func (lock *RedisLock) lifetimeTimer() (<-chan time.Time, func()) {
+	if lock.maxLifetime <= 0 {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(lock.maxLifetime)
+	return timer.C, func() { timer.Stop() }
+}
+
 // stopKeepAlive 停止续期 goroutine
 func (lock *RedisLock) stopKeepAlive() {
 	lock.mu.Lock()
@@ -123,11 +392,17 @@ func (lock *RedisLock) renew(ctx context.Context) (bool, error) {
 		end
 	`
 	ttl := int64(lock.timeout / time.Millisecond)
+	ctx, cancel := lock.opContext(ctx)
+	defer cancel()
 	result, err := lock.client.Eval(ctx, luaScript, []string{lock.lockName}, lock.lockValue, ttl).Result()
 	if err != nil {
-		return false, fmt.Errorf("cache: renew lock %q: %w", lock.lockName, err)
+		err = fmt.Errorf("cache: renew lock %q: %w", lock.lockName, err)
+		lock.observer.OnRenew(lock.lockName, lock.lockValue, false, err)
+		return false, err
 	}
-	return result.(int64) == 1, nil
+	held := result.(int64) == 1
+	lock.observer.OnRenew(lock.lockName, lock.lockValue, held, nil)
+	return held, nil
 }
 
 // release 执行 Redis 原子释放脚本并返回锁是否成功删除
@@ -139,11 +414,17 @@ func (lock *RedisLock) release(ctx context.Context) (bool, error) {
 			return 0
 		end
 	`
+	ctx, cancel := lock.opContext(ctx)
+	defer cancel()
 	result, err := lock.client.Eval(ctx, luaScript, []string{lock.lockName}, lock.lockValue).Result()
 	if err != nil {
-		return false, fmt.Errorf("cache: release lock %q: %w", lock.lockName, err)
+		err = fmt.Errorf("cache: release lock %q: %w", lock.lockName, err)
+		lock.observer.OnRelease(lock.lockName, lock.lockValue, false, err)
+		return false, err
 	}
-	return result.(int64) == 1, nil
+	released := result.(int64) == 1
+	lock.observer.OnRelease(lock.lockName, lock.lockValue, released, nil)
+	return released, nil
 }
 
 // Run 以 Lease 模型执行业务：获取锁、启动续租、执行业务、释放锁
@@ -198,6 +479,8 @@ func (lock *RedisLock) startRenewal(ctx context.Context, cancel context.CancelFu
 		defer close(errCh)
 		ticker := time.NewTicker(lock.timeout / 2)
 		defer ticker.Stop()
+		expired, stopExpiry := lock.lifetimeTimer()
+		defer stopExpiry()
 		for {
 			select {
 			case <-ticker.C:
@@ -212,6 +495,12 @@ func (lock *RedisLock) startRenewal(ctx context.Context, cancel context.CancelFu
 					cancel()
 					return
 				}
+			case <-expired:
+				if lock.cancelTask {
+					errCh <- ErrLockMaxLifetime
+					cancel()
+				}
+				return
 			case <-stopCh:
 				return
 			case <-ctx.Done():
@@ -237,3 +526,9 @@ func (lock *RedisLock) TryLock(ctx context.Context, fn func() error) error {
 		return fn()
 	})
 }
+
+// TryLockContext 尝试获取锁并执行 fn，续租失败或锁丢失时取消传给 fn 的 context
+// 长时间任务应监听 ctx.Done() 以便在失去独占性后及时中止
+func (lock *RedisLock) TryLockContext(ctx context.Context, fn func(ctx context.Context) error) error {
+	return lock.Run(ctx, fn)
+}
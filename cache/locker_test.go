@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,7 +14,7 @@ import (
 )
 
 // skipIfRedisUnavailable 在 Redis 不可用时跳过测试
-func skipIfRedisUnavailable(t *testing.T, client *redis.Client) {
+func skipIfRedisUnavailable(t testing.TB, client *redis.Client) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
@@ -147,3 +149,340 @@ func TestRedisLockRunCtxPassedThrough(t *testing.T) {
 		t.Fatal("Run did not return after parent ctx cancel")
 	}
 }
+
+// TestRedisLockTryLockContextLockLost 验证锁被强制删除后 fn 的 context 被取消
+func TestRedisLockTryLockContextLockLost(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	lock := NewRedisLock(client, "test_trylock_ctx_lost", time.Second)
+	defer func() {
+		_ = client.Del(context.Background(), "test_trylock_ctx_lost").Err()
+	}()
+
+	err := lock.TryLockContext(context.Background(), func(ctx context.Context) error {
+		// 模拟锁被外部强制删除
+		if err := client.Del(ctx, "test_trylock_ctx_lost").Err(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(3 * time.Second):
+			t.Error("fn context should be cancelled after lock is lost")
+			return nil
+		}
+	})
+
+	assert.ErrorIs(t, err, ErrLockLost, "TryLockContext should report lock loss")
+	assert.ErrorIs(t, err, context.Canceled, "fn should observe context cancellation")
+}
+
+// TestRedisLockKeepAliveContext 验证 ctx 取消后续期 goroutine 退出并允许重新启动
+func TestRedisLockKeepAliveContext(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	lock := NewRedisLock(client, "test_keepalive_ctx", time.Second)
+	defer func() {
+		_ = client.Del(context.Background(), "test_keepalive_ctx").Err()
+	}()
+
+	locked, err := lock.Acquire(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, locked)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lock.KeepAliveContext(ctx)
+	cancel()
+	assert.Eventually(t, func() bool {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		return !lock.keepAlive
+	}, time.Second, 10*time.Millisecond, "keep alive should stop after ctx cancel")
+
+	assert.Nil(t, lock.Release(context.Background()))
+}
+
+// TestRedisLockOwner 验证持锁期间 Owner 返回配置的持有者标识，释放后返回空字符串
+func TestRedisLockOwner(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	owner := "worker-1:1234:abcd"
+	lock := NewRedisLock(client, "test_lock_owner", 3*time.Second, WithLockOwner(owner))
+	ctx := context.Background()
+	defer func() {
+		_ = client.Del(ctx, "test_lock_owner").Err()
+	}()
+
+	locked, err := lock.Acquire(ctx)
+	assert.Nil(t, err)
+	assert.True(t, locked)
+
+	got, err := lock.Owner(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, owner, got, "Owner should return configured identity while held")
+	held, err := lock.IsLocked(ctx)
+	assert.Nil(t, err)
+	assert.True(t, held, "IsLocked should be true while held")
+
+	assert.Nil(t, lock.Release(ctx))
+
+	got, err = lock.Owner(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, got, "Owner should be empty after release")
+	held, err = lock.IsLocked(ctx)
+	assert.Nil(t, err)
+	assert.False(t, held, "IsLocked should be false after release")
+}
+
+// TestRedisLockAcquireWithTimeoutContention 验证 50 个竞争者退避重试时始终只有一个持有者且重试次数有界
+func TestRedisLockAcquireWithTimeoutContention(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	ctx := context.Background()
+	defer func() {
+		_ = client.Del(ctx, "test_acquire_backoff").Err()
+	}()
+
+	const contenders = 50
+	const maxWait = 3 * time.Second
+	var (
+		mu        sync.Mutex
+		holders   int
+		maxHolder int
+		acquired  int
+	)
+	attempts := make([]int, contenders)
+
+	var wg sync.WaitGroup
+	for i := 0; i < contenders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lock := NewRedisLock(client, "test_acquire_backoff", 3*time.Second, WithRetryBackoff(5*time.Millisecond, 100*time.Millisecond))
+			locked, n, err := lock.acquireWithBackoff(ctx, maxWait)
+			attempts[i] = n
+			assert.Nil(t, err)
+			if !locked {
+				return
+			}
+			mu.Lock()
+			holders++
+			acquired++
+			maxHolder = max(maxHolder, holders)
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			assert.Nil(t, lock.Release(ctx))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, maxHolder, "Only one contender should hold the lock at a time")
+	assert.Positive(t, acquired, "At least one contender should acquire the lock")
+	// 每次退避的期望值不小于 base/2，重试次数应远小于 maxWait/base
+	bound := int(maxWait / (5 * time.Millisecond))
+	for i, n := range attempts {
+		assert.LessOrEqual(t, n, bound, "contender %d retried too many times", i)
+	}
+}
+
+// TestRedisLockBackoffCapped 验证退避时长不超过配置的上限
+func TestRedisLockBackoffCapped(t *testing.T) {
+	lock := NewRedisLock(nil, "test_backoff_cap", time.Second, WithRetryBackoff(10*time.Millisecond, 80*time.Millisecond))
+	for attempt := 1; attempt < 100; attempt++ {
+		wait := lock.backoff(attempt)
+		assert.GreaterOrEqual(t, wait, time.Duration(0))
+		assert.LessOrEqual(t, wait, 80*time.Millisecond)
+	}
+}
+
+// TestRedisLockCancelledContext 验证已取消的 context 下 Acquire 立即返回错误
+func TestRedisLockCancelledContext(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	lock := NewRedisLock(client, "test_lock_cancelled_ctx", time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	locked, err := lock.Acquire(ctx)
+	assert.False(t, locked)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "Acquire should return promptly")
+}
+
+// TestRedisLockOperationTimeout 验证 Redis 无响应时单次操作在 opTimeout 内返回
+func TestRedisLockOperationTimeout(t *testing.T) {
+	// 只接受连接不回复任何数据，模拟卡死的 Redis
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), ReadTimeout: -1, MaxRetries: -1, ContextTimeoutEnabled: true})
+	defer client.Close()
+	lock := NewRedisLock(client, "test_lock_op_timeout", time.Second, WithOperationTimeout(200*time.Millisecond))
+
+	start := time.Now()
+	_, err = lock.Acquire(context.Background())
+	assert.Error(t, err, "Acquire should fail when redis does not respond")
+	assert.Less(t, time.Since(start), time.Second, "Acquire should be bounded by the operation timeout")
+}
+
+// recordingLockObserver 按顺序记录锁生命周期事件的测试观察者
+type recordingLockObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (observer *recordingLockObserver) record(event string) {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	observer.events = append(observer.events, event)
+}
+
+func (observer *recordingLockObserver) OnAcquireAttempt(name, owner string) {
+	observer.record(fmt.Sprintf("attempt %s %s", name, owner))
+}
+
+func (observer *recordingLockObserver) OnAcquired(name, owner string) {
+	observer.record(fmt.Sprintf("acquired %s %s", name, owner))
+}
+
+func (observer *recordingLockObserver) OnAcquireFailed(name, owner string, err error) {
+	observer.record(fmt.Sprintf("failed %s %s %v", name, owner, err))
+}
+
+func (observer *recordingLockObserver) OnRenew(name, owner string, held bool, err error) {
+	observer.record(fmt.Sprintf("renew %s %s %t %v", name, owner, held, err))
+}
+
+func (observer *recordingLockObserver) OnRelease(name, owner string, released bool, err error) {
+	observer.record(fmt.Sprintf("release %s %s %t %v", name, owner, released, err))
+}
+
+// TestRedisLockObserver 验证成功的 TryLock 依次触发 attempt、acquired、release 事件，竞争失败触发 failed 事件
+func TestRedisLockObserver(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	ctx := context.Background()
+	lockName := "test_lock_observer"
+	defer func() {
+		_ = client.Del(ctx, lockName).Err()
+	}()
+	_ = client.Del(ctx, lockName).Err()
+
+	observer := &recordingLockObserver{}
+	lock := NewRedisLock(client, lockName, 3*time.Second, WithLockOwner("owner-a"), WithObserver(observer))
+	err := lock.TryLock(ctx, func() error {
+		contender := NewRedisLock(client, lockName, 3*time.Second, WithLockOwner("owner-b"), WithObserver(observer))
+		locked, err := contender.Acquire(ctx)
+		assert.Nil(t, err)
+		assert.False(t, locked)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"attempt test_lock_observer owner-a",
+		"acquired test_lock_observer owner-a",
+		"attempt test_lock_observer owner-b",
+		"failed test_lock_observer owner-b <nil>",
+		"release test_lock_observer owner-a true <nil>",
+	}, observer.events)
+}
+
+// TestRedisLockMaxLifetime 验证任务运行超过最长续租时间后续租停止、锁按 TTL 过期，开启 cancelTask 时业务 context 被取消
+func TestRedisLockMaxLifetime(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	ctx := context.Background()
+	lockName := "test_lock_max_lifetime"
+	defer func() {
+		_ = client.Del(ctx, lockName).Err()
+	}()
+
+	t.Run("renewal stops and lock expires", func(t *testing.T) {
+		_ = client.Del(ctx, lockName).Err()
+		observer := &recordingLockObserver{}
+		lock := NewRedisLock(client, lockName, time.Second, WithMaxLifetime(1200*time.Millisecond, false), WithObserver(observer))
+		err := lock.Run(ctx, func(ctx context.Context) error {
+			time.Sleep(2500 * time.Millisecond)
+			assert.Nil(t, ctx.Err(), "task context should not be cancelled")
+			exists, err := client.Exists(ctx, lockName).Result()
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), exists, "lock should expire after renewal stops")
+			return nil
+		})
+		assert.Error(t, err, "release should report the expired lock")
+
+		renewals := 0
+		for _, event := range observer.events {
+			if event == fmt.Sprintf("renew %s %s true <nil>", lockName, lock.lockValue) {
+				renewals++
+			}
+		}
+		assert.Equal(t, 2, renewals, "renewal should stop after max lifetime")
+	})
+
+	t.Run("task context cancelled", func(t *testing.T) {
+		_ = client.Del(ctx, lockName).Err()
+		lock := NewRedisLock(client, lockName, time.Second, WithMaxLifetime(300*time.Millisecond, true))
+		start := time.Now()
+		err := lock.Run(ctx, func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("task context not cancelled")
+			}
+		})
+		assert.ErrorIs(t, err, ErrLockMaxLifetime)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+}
+
+// TestRedisLockAcquireWithToken 验证连续获取锁得到严格递增的 fencing token，竞争失败不消耗 token
+func TestRedisLockAcquireWithToken(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	ctx := context.Background()
+	lockName := "test_lock_fencing"
+	defer func() {
+		_ = client.Del(ctx, lockName, lockName+":fence").Err()
+	}()
+	_ = client.Del(ctx, lockName, lockName+":fence").Err()
+
+	var previous int64
+	for i := 0; i < 3; i++ {
+		lock := NewRedisLock(client, lockName, 3*time.Second)
+		token, ok, err := lock.AcquireWithToken(ctx)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Greater(t, token, previous, "fencing token should strictly increase")
+		assert.Equal(t, token, lock.Token())
+
+		contender := NewRedisLock(client, lockName, 3*time.Second)
+		contenderToken, ok, err := contender.AcquireWithToken(ctx)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+		assert.Zero(t, contenderToken)
+		assert.Zero(t, contender.Token())
+
+		assert.Nil(t, lock.Release(ctx))
+		previous = token
+	}
+	assert.Equal(t, int64(3), previous, "failed attempts should not consume tokens")
+}
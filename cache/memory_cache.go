@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultJanitorInterval = time.Minute
+
+// ErrNotInteger 对非整数值执行自增
+var ErrNotInteger = errors.New("cache: value is not an integer")
+
+// memoryEntry 内存缓存条目，expireAt 为零值表示不过期
+type memoryEntry struct {
+	value    string
+	expireAt time.Time
+}
+
+// expired 判断条目在 now 时是否已过期
+func (entry memoryEntry) expired(now time.Time) bool {
+	return !entry.expireAt.IsZero() && !now.Before(entry.expireAt)
+}
+
+// MemoryCache 并发安全的进程内 Cache 实现，读取时惰性判断过期，后台清理协程定期删除过期条目，
+// 语义与 RedisClient 对应方法保持一致，用于无 Redis 环境的单元测试
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	clock   Clock
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewMemoryCache 创建内存缓存并启动后台清理协程，janitorInterval <= 0 时默认 1 分钟，使用完毕需调用 Close
+func NewMemoryCache(janitorInterval time.Duration) *MemoryCache {
+	if janitorInterval <= 0 {
+		janitorInterval = defaultJanitorInterval
+	}
+	cache := &MemoryCache{
+		entries: make(map[string]memoryEntry),
+		clock:   realClock{},
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go cache.janitor(janitorInterval)
+	return cache
+}
+
+// janitor 定期删除过期条目
+func (c *MemoryCache) janitor(interval time.Duration) {
+	defer close(c.doneCh)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.deleteExpired()
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// deleteExpired 删除所有已过期条目
+func (c *MemoryCache) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for key, entry := range c.entries {
+		if entry.expired(now) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// lookup 在持锁状态下读取未过期的条目，已过期的条目顺带删除
+func (c *MemoryCache) lookup(key string) (memoryEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if entry.expired(c.clock.Now()) {
+		delete(c.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// expireAt 计算 ttl 对应的过期时间，ttl <= 0 时不过期
+func (c *MemoryCache) expireAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return c.clock.Now().Add(ttl)
+}
+
+// Get 获取单个key的值，key 不存在时返回空字符串
+func (c *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	val, _, err := c.GetExists(ctx, key)
+	return val, err
+}
+
+// GetExists 获取单个key的值，found 仅在 key 不存在时为 false
+func (c *MemoryCache) GetExists(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lookup(key)
+	return entry.value, ok, nil
+}
+
+// MGet 批量获取多个key的值，不存在的 key 对应 nil
+func (c *MemoryCache) MGet(_ context.Context, keys ...string) ([]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if entry, ok := c.lookup(key); ok {
+			values[i] = entry.value
+		}
+	}
+	return values, nil
+}
+
+// Set 设置单个key的值，ttl 为 redis.KeepTTL 时保留原有过期时间，ttl 为 0 时不过期
+func (c *MemoryCache) Set(_ context.Context, key string, val any, ttl time.Duration) error {
+	value, err := formatMemoryValue(val)
+	if err != nil {
+		return fmt.Errorf("cache: set %q: %w", key, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expireAt := c.expireAt(ttl)
+	if ttl == redis.KeepTTL {
+		if entry, ok := c.lookup(key); ok {
+			expireAt = entry.expireAt
+		}
+	}
+	c.entries[key] = memoryEntry{value: value, expireAt: expireAt}
+	return nil
+}
+
+// SetNX 仅当 key 不存在时写入
+func (c *MemoryCache) SetNX(_ context.Context, key string, val any, ttl time.Duration) (bool, error) {
+	value, err := formatMemoryValue(val)
+	if err != nil {
+		return false, fmt.Errorf("cache: setnx %q: %w", key, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lookup(key); ok {
+		return false, nil
+	}
+	c.entries[key] = memoryEntry{value: value, expireAt: c.expireAt(ttl)}
+	return true, nil
+}
+
+// Del 删除指定的key
+func (c *MemoryCache) Del(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// Exists 返回存在的 key 数量，重复的 key 重复计数（与 Redis 一致）
+func (c *MemoryCache) Exists(_ context.Context, keys ...string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var count int64
+	for _, key := range keys {
+		if _, ok := c.lookup(key); ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Expire 设置key的过期时间，key 不存在时为空操作，ttl <= 0 时立即删除（与 Redis 一致）
+func (c *MemoryCache) Expire(_ context.Context, key string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	if ttl <= 0 {
+		delete(c.entries, key)
+		return nil
+	}
+	entry.expireAt = c.expireAt(ttl)
+	c.entries[key] = entry
+	return nil
+}
+
+// TTL 获取key的剩余过期时间，key 不存在时返回 -2ns，未设置过期时返回 -1ns（与 RedisClient 一致）
+func (c *MemoryCache) TTL(_ context.Context, key string) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lookup(key)
+	if !ok {
+		return -2, nil
+	}
+	if entry.expireAt.IsZero() {
+		return -1, nil
+	}
+	return entry.expireAt.Sub(c.clock.Now()), nil
+}
+
+// Incr 对key的值进行自增操作
+func (c *MemoryCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+// IncrBy 对key的值进行指定步长的自增操作，key 不存在时按 0 计算且保留原有过期时间
+func (c *MemoryCache) IncrBy(_ context.Context, key string, step int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lookup(key)
+	var current int64
+	if ok {
+		parsed, err := strconv.ParseInt(entry.value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cache: incrby %q: %w", key, ErrNotInteger)
+		}
+		current = parsed
+	}
+	current += step
+	entry.value = strconv.FormatInt(current, 10)
+	c.entries[key] = entry
+	return current, nil
+}
+
+// Close 停止后台清理协程，可重复调用
+func (c *MemoryCache) Close() error {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+		<-c.doneCh
+	})
+	return nil
+}
+
+// formatMemoryValue 按 go-redis 的参数编码规则将值转为字符串
+func formatMemoryValue(val any) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("can't marshal %T (implement encoding.BinaryMarshaler)", val)
+	}
+}
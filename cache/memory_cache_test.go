@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryCacheBasic 验证内存缓存的读写、NX、批量读取、自增与删除语义与 RedisClient 一致
+func TestMemoryCacheBasic(t *testing.T) {
+	memory := NewMemoryCache(time.Minute)
+	defer memory.Close()
+	ctx := context.Background()
+
+	assert.Nil(t, memory.Set(ctx, "name", "alice", 0))
+	assert.Nil(t, memory.Set(ctx, "flag", true, 0))
+	value, err := memory.Get(ctx, "name")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", value)
+	value, found, err := memory.GetExists(ctx, "missing")
+	assert.Nil(t, err)
+	assert.False(t, found)
+	assert.Equal(t, "", value)
+
+	created, err := memory.SetNX(ctx, "name", "bob", 0)
+	assert.Nil(t, err)
+	assert.False(t, created, "existing key should not be overwritten")
+	values, err := memory.MGet(ctx, "name", "missing", "flag")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"alice", nil, "1"}, values)
+
+	count, err := memory.Incr(ctx, "counter")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = memory.IncrBy(ctx, "counter", 9)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), count)
+	_, err = memory.Incr(ctx, "name")
+	assert.ErrorIs(t, err, ErrNotInteger)
+
+	exists, err := memory.Exists(ctx, "name", "counter", "missing")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), exists)
+	assert.Nil(t, memory.Del(ctx, "name", "counter"))
+	exists, err = memory.Exists(ctx, "name", "counter")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), exists)
+}
+
+// TestMemoryCacheTTL 验证过期时间推进后 key 不可读，TTL 返回值与 Redis 约定一致，清理协程删除过期条目
+func TestMemoryCacheTTL(t *testing.T) {
+	memory := NewMemoryCache(10 * time.Millisecond)
+	defer memory.Close()
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	memory.mu.Lock()
+	memory.clock = clock
+	memory.mu.Unlock()
+	ctx := context.Background()
+
+	assert.Nil(t, memory.Set(ctx, "session", "token", time.Minute))
+	assert.Nil(t, memory.Set(ctx, "forever", "v", 0))
+	ttl, err := memory.TTL(ctx, "session")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, ttl)
+	ttl, err = memory.TTL(ctx, "forever")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(-1), ttl)
+
+	clock.Advance(30 * time.Second)
+	assert.Nil(t, memory.Expire(ctx, "forever", 10*time.Second))
+	value, err := memory.Get(ctx, "session")
+	assert.Nil(t, err)
+	assert.Equal(t, "token", value)
+
+	clock.Advance(30 * time.Second)
+	_, found, err := memory.GetExists(ctx, "session")
+	assert.Nil(t, err)
+	assert.False(t, found, "key should expire after its ttl")
+	ttl, err = memory.TTL(ctx, "session")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(-2), ttl)
+
+	assert.Eventually(t, func() bool {
+		memory.mu.Lock()
+		defer memory.mu.Unlock()
+		_, ok := memory.entries["forever"]
+		return !ok
+	}, time.Second, 10*time.Millisecond, "janitor should remove expired entries")
+}
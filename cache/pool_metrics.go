@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// poolMetricsScope 连接池指标的 instrumentation scope 名称
+const poolMetricsScope = "github.com/ethereal3x/apc/cache"
+
+// PoolStats 返回底层连接池统计（命中、未命中、超时及总/空闲/过期连接数）
+func (r *RedisClient) PoolStats() *redis.PoolStats {
+	return r.client.PoolStats()
+}
+
+// WithPoolMetrics 通过全局 MeterProvider 将连接池统计发布为 redis.pool.* gauge，附带 server.address 属性，
+// 由 MeterProvider 的 reader 按其采集周期在后台定期读取 PoolStats，未配置 MeterProvider 时为空操作；
+// 注册失败通过 otel.Handle 上报，Close 时注销采集回调
+func WithPoolMetrics() RedisClientOption {
+	return func(r *RedisClient) {
+		registration, err := registerPoolMetrics(otel.Meter(poolMetricsScope), r)
+		if err != nil {
+			otel.Handle(fmt.Errorf("cache: register pool metrics: %w", err))
+			return
+		}
+		r.poolMetrics = registration
+	}
+}
+
+// poolGauge 连接池统计项与 gauge 名称的对应关系
+type poolGauge struct {
+	name        string
+	description string
+	value       func(stats *redis.PoolStats) int64
+}
+
+var poolGauges = []poolGauge{
+	{"redis.pool.hits", "连接池命中次数", func(stats *redis.PoolStats) int64 { return int64(stats.Hits) }},
+	{"redis.pool.misses", "连接池未命中次数", func(stats *redis.PoolStats) int64 { return int64(stats.Misses) }},
+	{"redis.pool.timeouts", "等待连接超时次数", func(stats *redis.PoolStats) int64 { return int64(stats.Timeouts) }},
+	{"redis.pool.total_conns", "连接总数", func(stats *redis.PoolStats) int64 { return int64(stats.TotalConns) }},
+	{"redis.pool.idle_conns", "空闲连接数", func(stats *redis.PoolStats) int64 { return int64(stats.IdleConns) }},
+	{"redis.pool.stale_conns", "被移除的过期连接数", func(stats *redis.PoolStats) int64 { return int64(stats.StaleConns) }},
+}
+
+// registerPoolMetrics 创建连接池 gauge 并注册统一的采集回调
+func registerPoolMetrics(meter metric.Meter, r *RedisClient) (metric.Registration, error) {
+	gauges := make([]metric.Int64ObservableGauge, 0, len(poolGauges))
+	observables := make([]metric.Observable, 0, len(poolGauges))
+	for _, definition := range poolGauges {
+		gauge, err := meter.Int64ObservableGauge(definition.name, metric.WithDescription(definition.description))
+		if err != nil {
+			return nil, err
+		}
+		gauges = append(gauges, gauge)
+		observables = append(observables, gauge)
+	}
+	attrs := metric.WithAttributes(attribute.String("server.address", r.client.Options().Addr))
+	return meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		stats := r.client.PoolStats()
+		for i, definition := range poolGauges {
+			observer.ObserveInt64(gauges[i], definition.value(stats), attrs)
+		}
+		return nil
+	}, observables...)
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TestRedisClientPoolStats 验证执行命令后 PoolStats 至少反映一个连接
+func TestRedisClientPoolStats(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+
+	assert.Nil(t, redisClient.Set(context.Background(), "test_pool_stats", "v", time.Minute))
+	stats := redisClient.PoolStats()
+	assert.NotNil(t, stats)
+	assert.GreaterOrEqual(t, stats.TotalConns, uint32(1))
+}
+
+// TestRedisClientPoolMetrics 验证 WithPoolMetrics 注册的 gauge 可被 reader 采集，Close 后注销
+func TestRedisClientPoolMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	defer func() {
+		otel.SetMeterProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	}()
+
+	base, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	redisClient := NewRedisClient(base.client, WithPoolMetrics())
+	ctx := context.Background()
+	assert.Nil(t, redisClient.Set(ctx, "test_pool_metrics", "v", time.Minute))
+
+	var collected metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(ctx, &collected))
+	values := map[string]int64{}
+	for _, scope := range collected.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if gauge, ok := m.Data.(metricdata.Gauge[int64]); ok && len(gauge.DataPoints) > 0 {
+				values[m.Name] = gauge.DataPoints[0].Value
+			}
+		}
+	}
+	assert.Len(t, values, len(poolGauges))
+	assert.GreaterOrEqual(t, values["redis.pool.total_conns"], int64(1))
+
+	assert.Nil(t, redisClient.Close())
+	collected = metricdata.ResourceMetrics{}
+	assert.Nil(t, reader.Collect(ctx, &collected))
+	for _, scope := range collected.ScopeMetrics {
+		assert.Empty(t, scope.Metrics, "metrics should stop after Close")
+	}
+}
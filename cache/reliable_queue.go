@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrQueueEmpty 队列中暂无可消费的元素
+var ErrQueueEmpty = errors.New("cache: queue empty")
+
+// ReliableQueue 基于 Redis 列表的可靠队列：消费时通过 LMOVE 将元素原子地移入消费者专属的处理中列表，
+// Ack 后才从处理中列表删除，消费者崩溃时可通过 Recover 将其未确认的元素重新入队
+type ReliableQueue struct {
+	client     *RedisClient
+	name       string
+	consumer   string
+	processing string
+}
+
+// NewReliableQueue 创建可靠队列，consumer 为消费者标识，需在消费者之间唯一且重启后保持不变以便恢复
+func NewReliableQueue(client *RedisClient, name, consumer string) *ReliableQueue {
+	return &ReliableQueue{
+		client:     client,
+		name:       name,
+		consumer:   consumer,
+		processing: processingKey(name, consumer),
+	}
+}
+
+// processingKey 返回消费者处理中列表的 key
+func processingKey(name, consumer string) string {
+	return name + ":processing:" + consumer
+}
+
+// Push 向队列尾部追加元素
+func (queue *ReliableQueue) Push(ctx context.Context, val string) error {
+	if err := queue.client.client.LPush(ctx, queue.name, val).Err(); err != nil {
+		return queue.client.errorf("cache: queue push %q: %w", queue.client.maskKey(queue.name), err)
+	}
+	return nil
+}
+
+// Consume 取出最早入队的元素并移入处理中列表，返回元素与确认令牌，队列为空时返回 ErrQueueEmpty
+func (queue *ReliableQueue) Consume(ctx context.Context) (val string, ackToken string, err error) {
+	val, err = queue.client.client.LMove(ctx, queue.name, queue.processing, "RIGHT", "LEFT").Result()
+	if errors.Is(err, redis.Nil) {
+		return "", "", ErrQueueEmpty
+	}
+	if err != nil {
+		return "", "", queue.client.errorf("cache: queue consume %q: %w", queue.client.maskKey(queue.name), err)
+	}
+	return val, val, nil
+}
+
+// Ack 确认元素处理完成并从处理中列表删除，令牌不存在时返回错误
+func (queue *ReliableQueue) Ack(ctx context.Context, ackToken string) error {
+	removed, err := queue.client.client.LRem(ctx, queue.processing, 1, ackToken).Result()
+	if err != nil {
+		return queue.client.errorf("cache: queue ack %q: %w", queue.client.maskKey(queue.name), err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("cache: queue ack %q: token not found in processing list", queue.client.maskKey(queue.name))
+	}
+	return nil
+}
+
+// Recover 将指定消费者处理中列表里未确认的元素按原顺序重新放回队列头部优先消费，返回重新入队的数量
+// 应仅对已确认崩溃或下线的消费者调用，否则会导致元素被重复处理
+func (queue *ReliableQueue) Recover(ctx context.Context, consumer string) (int64, error) {
+	luaScript := `
+		local moved = 0
+		while true do
+			local val = redis.call("LPOP", KEYS[1])
+			if not val then
+				return moved
+			end
+			redis.call("RPUSH", KEYS[2], val)
+			moved = moved + 1
+		end
+	`
+	keys := []string{processingKey(queue.name, consumer), queue.name}
+	moved, err := queue.client.client.Eval(ctx, luaScript, keys).Int64()
+	if err != nil {
+		return 0, queue.client.errorf("cache: queue recover %q consumer %q: %w", queue.client.maskKey(queue.name), consumer, err)
+	}
+	return moved, nil
+}
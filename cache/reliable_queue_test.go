@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReliableQueueRecover 验证未确认的元素在消费者崩溃后经 Recover 重新投递，已确认的元素不再出现
+func TestReliableQueueRecover(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	name := "test_reliable_queue"
+	keys := []string{name, processingKey(name, "worker-1"), processingKey(name, "worker-2")}
+	_ = redisClient.Del(ctx, keys...)
+	defer func() {
+		_ = redisClient.Del(ctx, keys...)
+	}()
+
+	crashed := NewReliableQueue(redisClient, name, "worker-1")
+	for _, job := range []string{"job-1", "job-2", "job-3"} {
+		assert.Nil(t, crashed.Push(ctx, job))
+	}
+
+	val, token, err := crashed.Consume(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "job-1", val, "Queue should be FIFO")
+	assert.Nil(t, crashed.Ack(ctx, token))
+
+	// 消费 job-2 后未确认即崩溃
+	val, _, err = crashed.Consume(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "job-2", val)
+
+	survivor := NewReliableQueue(redisClient, name, "worker-2")
+	moved, err := survivor.Recover(ctx, "worker-1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), moved, "Only the unacked job should be requeued")
+
+	var delivered []string
+	for {
+		val, token, err := survivor.Consume(ctx)
+		if err == ErrQueueEmpty {
+			break
+		}
+		assert.Nil(t, err)
+		assert.Nil(t, survivor.Ack(ctx, token))
+		delivered = append(delivered, val)
+	}
+	assert.Equal(t, []string{"job-2", "job-3"}, delivered, "Recovered job should be redelivered before newer jobs")
+	assert.Error(t, survivor.Ack(ctx, "job-unknown"), "Unknown token should not ack")
+}
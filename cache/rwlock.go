@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const defaultRWLockRetryInterval = 50 * time.Millisecond
+
+// RWLock 基于 Redis 的分布式读写锁，读锁可并发持有，写锁与读锁、写锁互斥
+// 每个实例代表一个持有者，读者以唯一标识记录在有序集合中，分值为该读者的过期时间，未释放即崩溃的读者到期后被清理；
+// 写者等待时会登记写意向阻止新读者进入，避免写饥饿，放弃等待时撤销写意向
+// 三个 key 共用 {name} 哈希标签，集群模式下位于同一 slot
+type RWLock struct {
+	client        *RedisClient
+	name          string
+	token         string
+	timeout       time.Duration
+	retryInterval time.Duration
+}
+
+// NewRWLock 创建分布式读写锁实例，timeout 为读写锁的最长持有时间
+func NewRWLock(client *RedisClient, name string, timeout time.Duration) *RWLock {
+	if timeout <= 0 {
+		timeout = defaultRedisLockTimeout
+	}
+	return &RWLock{
+		client:        client,
+		name:          name,
+		token:         DefaultLockOwner(),
+		timeout:       timeout,
+		retryInterval: defaultRWLockRetryInterval,
+	}
+}
+
+// writerKey 写锁 key
+func (lock *RWLock) writerKey() string {
+	return "{" + lock.name + "}:writer"
+}
+
+// readersKey 读者有序集合 key，成员为读者标识，分值为过期时间（毫秒时间戳）
+func (lock *RWLock) readersKey() string {
+	return "{" + lock.name + "}:readers"
+}
+
+// intentKey 写意向 key，存在时新读者需等待
+func (lock *RWLock) intentKey() string {
+	return "{" + lock.name + "}:intent"
+}
+
+// pruneReadersScript 以 Redis 服务端时间清理已过期的读者，定义局部变量 now（毫秒），供读写脚本共用
+const pruneReadersScript = `
+	local time = redis.call("TIME")
+	local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+	redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", now)
+`
+
+// TryRLock 尝试获取读锁，存在写锁或写意向时立即返回 false
+// 每个读者单独记录过期时间，读者集合 key 的过期时间仅用于回收，跟随最晚过期的读者
+func (lock *RWLock) TryRLock(ctx context.Context) (bool, error) {
+	luaScript := pruneReadersScript + `
+		if redis.call("EXISTS", KEYS[1]) == 1 or redis.call("EXISTS", KEYS[3]) == 1 then
+			return 0
+		end
+		redis.call("ZADD", KEYS[2], now + tonumber(ARGV[2]), ARGV[1])
+		local latest = redis.call("ZRANGE", KEYS[2], -1, -1, "WITHSCORES")
+		redis.call("PEXPIREAT", KEYS[2], latest[2])
+		return 1
+	`
+	keys := []string{lock.writerKey(), lock.readersKey(), lock.intentKey()}
+	ttl := int64(lock.timeout / time.Millisecond)
+	result, err := lock.client.client.Eval(ctx, luaScript, keys, lock.token, ttl).Result()
+	if err != nil {
+		return false, lock.client.errorf("cache: rlock %q: %w", lock.client.maskKey(lock.name), err)
+	}
+	return result.(int64) == 1, nil
+}
+
+// RLock 阻塞获取读锁，直至成功或 ctx 结束
+func (lock *RWLock) RLock(ctx context.Context) error {
+	return lock.wait(ctx, "rlock", lock.TryRLock)
+}
+
+// RUnlock 释放当前实例持有的读锁
+func (lock *RWLock) RUnlock(ctx context.Context) error {
+	removed, err := lock.client.client.ZRem(ctx, lock.readersKey(), lock.token).Result()
+	if err != nil {
+		return lock.client.errorf("cache: runlock %q: %w", lock.client.maskKey(lock.name), err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("cache: runlock %q: read lock not held", lock.client.maskKey(lock.name))
+	}
+	return nil
+}
+
+// TryLock 尝试获取写锁，仍有未过期的读者时登记写意向并返回 false
+func (lock *RWLock) TryLock(ctx context.Context) (bool, error) {
+	luaScript := pruneReadersScript + `
+		if redis.call("EXISTS", KEYS[1]) == 1 then
+			return 0
+		end
+		if redis.call("ZCARD", KEYS[2]) > 0 then
+			redis.call("SET", KEYS[3], ARGV[1], "PX", ARGV[2])
+			return 0
+		end
+		redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+		redis.call("DEL", KEYS[3])
+		return 1
+	`
+	keys := []string{lock.writerKey(), lock.readersKey(), lock.intentKey()}
+	ttl := int64(lock.timeout / time.Millisecond)
+	result, err := lock.client.client.Eval(ctx, luaScript, keys, lock.token, ttl).Result()
+	if err != nil {
+		return false, lock.client.errorf("cache: lock %q: %w", lock.client.maskKey(lock.name), err)
+	}
+	return result.(int64) == 1, nil
+}
+
+// Lock 阻塞获取写锁，等待已有读者全部释放，直至成功或 ctx 结束；ctx 结束放弃等待时撤销本实例登记的写意向
+func (lock *RWLock) Lock(ctx context.Context) error {
+	err := lock.wait(ctx, "lock", lock.TryLock)
+	if err != nil && ctx.Err() != nil {
+		lock.abandonIntent(ctx)
+	}
+	return err
+}
+
+// abandonIntent 撤销本实例登记的写意向，避免放弃等待后新读者仍被阻塞至写意向过期；其他写者登记的写意向保持不变
+// 调用时 ctx 已结束，使用脱离取消的短超时 context 执行
+func (lock *RWLock) abandonIntent(ctx context.Context) {
+	luaScript := `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		end
+		return 0
+	`
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lock.retryInterval+time.Second)
+	defer cancel()
+	_ = lock.client.client.Eval(cleanupCtx, luaScript, []string{lock.intentKey()}, lock.token).Err()
+}
+
+// Unlock 释放当前实例持有的写锁，仅当锁值匹配时才删除
+func (lock *RWLock) Unlock(ctx context.Context) error {
+	luaScript := `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		else
+			return 0
+		end
+	`
+	result, err := lock.client.client.Eval(ctx, luaScript, []string{lock.writerKey()}, lock.token).Result()
+	if err != nil {
+		return lock.client.errorf("cache: unlock %q: %w", lock.client.maskKey(lock.name), err)
+	}
+	if result.(int64) != 1 {
+		return fmt.Errorf("cache: unlock %q: write lock already lost or value mismatch", lock.client.maskKey(lock.name))
+	}
+	return nil
+}
+
+// wait 按固定间隔重试 try 直至获取成功或 ctx 结束
+func (lock *RWLock) wait(ctx context.Context, op string, try func(ctx context.Context) (bool, error)) error {
+	ticker := time.NewTicker(lock.retryInterval)
+	defer ticker.Stop()
+	for {
+		acquired, err := try(ctx)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cache: %s %q: %w", op, lock.client.maskKey(lock.name), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
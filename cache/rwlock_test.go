@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRWLocks 创建指定数量共享同一名称的读写锁实例
+func newTestRWLocks(t *testing.T, name string, n int) []*RWLock {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	locks := make([]*RWLock, n)
+	for i := range locks {
+		locks[i] = NewRWLock(redisClient, name, 5*time.Second)
+	}
+	t.Cleanup(func() {
+		_ = client.Del(context.Background(), locks[0].writerKey(), locks[0].readersKey(), locks[0].intentKey()).Err()
+	})
+	return locks
+}
+
+// TestRWLockConcurrentReaders 验证多个读者可同时持有读锁
+func TestRWLockConcurrentReaders(t *testing.T) {
+	locks := newTestRWLocks(t, "test_rwlock_readers", 3)
+	ctx := context.Background()
+
+	for _, lock := range locks {
+		assert.Nil(t, lock.RLock(ctx), "Readers should acquire read lock concurrently")
+	}
+	for _, lock := range locks {
+		assert.Nil(t, lock.RUnlock(ctx), "Readers should release read lock")
+	}
+}
+
+// TestRWLockWriterWaitsForReaders 验证写者等待所有读者释放后才能获取写锁
+func TestRWLockWriterWaitsForReaders(t *testing.T) {
+	locks := newTestRWLocks(t, "test_rwlock_writer_wait", 3)
+	reader1, reader2, writer := locks[0], locks[1], locks[2]
+	ctx := context.Background()
+
+	assert.Nil(t, reader1.RLock(ctx))
+	assert.Nil(t, reader2.RLock(ctx))
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- writer.Lock(ctx)
+	}()
+
+	assert.Nil(t, reader1.RUnlock(ctx))
+	select {
+	case <-acquired:
+		t.Fatal("writer should wait while a reader still holds the lock")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// 写意向登记后新读者不能再进入
+	ok, err := reader1.TryRLock(ctx)
+	assert.Nil(t, err)
+	assert.False(t, ok, "New readers should wait behind a pending writer")
+
+	assert.Nil(t, reader2.RUnlock(ctx))
+	select {
+	case err := <-acquired:
+		assert.Nil(t, err, "Writer should acquire after readers drain")
+	case <-time.After(2 * time.Second):
+		t.Fatal("writer did not acquire after readers drained")
+	}
+	assert.Nil(t, writer.Unlock(ctx))
+}
+
+// TestRWLockReadersWaitForWriter 验证读者等待写锁释放后才能获取读锁
+func TestRWLockReadersWaitForWriter(t *testing.T) {
+	locks := newTestRWLocks(t, "test_rwlock_reader_wait", 3)
+	writer, reader1, reader2 := locks[0], locks[1], locks[2]
+	ctx := context.Background()
+
+	assert.Nil(t, writer.Lock(ctx))
+
+	acquired := make(chan error, 2)
+	for _, reader := range []*RWLock{reader1, reader2} {
+		go func(reader *RWLock) {
+			acquired <- reader.RLock(ctx)
+		}(reader)
+	}
+
+	select {
+	case <-acquired:
+		t.Fatal("readers should wait while writer holds the lock")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	assert.Nil(t, writer.Unlock(ctx))
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-acquired:
+			assert.Nil(t, err, "Readers should acquire after writer releases")
+		case <-time.After(2 * time.Second):
+			t.Fatal("reader did not acquire after writer released")
+		}
+	}
+	assert.Nil(t, reader1.RUnlock(ctx))
+	assert.Nil(t, reader2.RUnlock(ctx))
+
+	// 写锁超时时取消等待
+	assert.Nil(t, writer.Lock(ctx))
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, reader1.RLock(waitCtx), context.DeadlineExceeded)
+	assert.Nil(t, writer.Unlock(ctx))
+}
+
+// TestRWLockStaleReaderExpires 验证未释放即崩溃的读者到期后被清理，持续到来的新读者不会延长其持有时间导致写者饥饿
+func TestRWLockStaleReaderExpires(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	crashed := NewRWLock(redisClient, "test_rwlock_stale", 300*time.Millisecond)
+	writer := NewRWLock(redisClient, "test_rwlock_stale", 5*time.Second)
+
+	assert.Nil(t, crashed.RLock(ctx))
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		reader := NewRWLock(redisClient, "test_rwlock_stale", 300*time.Millisecond)
+		if ok, err := reader.TryRLock(ctx); err != nil || !ok {
+			break
+		}
+		assert.Nil(t, reader.RUnlock(ctx))
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	assert.Nil(t, writer.Lock(lockCtx), "Writer should acquire once the crashed reader expires")
+	assert.Nil(t, writer.Unlock(ctx))
+}
+
+// TestRWLockAbandonedWriterClearsIntent 验证写者放弃等待后撤销写意向，新读者可立即进入
+func TestRWLockAbandonedWriterClearsIntent(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	reader := NewRWLock(redisClient, "test_rwlock_abandon", 5*time.Second)
+	writer := NewRWLock(redisClient, "test_rwlock_abandon", 5*time.Second)
+	newReader := NewRWLock(redisClient, "test_rwlock_abandon", 5*time.Second)
+
+	assert.Nil(t, reader.RLock(ctx))
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, writer.Lock(waitCtx), context.DeadlineExceeded)
+
+	ok, err := newReader.TryRLock(ctx)
+	assert.Nil(t, err)
+	assert.True(t, ok, "Readers should not be blocked by an abandoned writer intent")
+	assert.Nil(t, newReader.RUnlock(ctx))
+	assert.Nil(t, reader.RUnlock(ctx))
+}
+
+// TestRWLockKeysShareHashTag 验证读写锁的 key 共用同一哈希标签，集群模式下多 key 脚本不会跨 slot
+func TestRWLockKeysShareHashTag(t *testing.T) {
+	lock := NewRWLock(nil, "orders", time.Second)
+	assert.Equal(t, "{orders}:writer", lock.writerKey())
+	assert.Equal(t, "{orders}:readers", lock.readersKey())
+	assert.Equal(t, "{orders}:intent", lock.intentKey())
+}
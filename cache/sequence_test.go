@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSequenceNextBatch 验证预留 100 个值后下一次 Next 返回 start+100
+func TestSequenceNextBatch(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	sequence := NewSequence(redisClient)
+	ctx := context.Background()
+
+	first, err := sequence.Next(ctx, "order")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), first)
+
+	start, err := sequence.NextBatch(ctx, "order", 100)
+	assert.Nil(t, err)
+	assert.Equal(t, first+1, start)
+
+	next, err := sequence.Next(ctx, "order")
+	assert.Nil(t, err)
+	assert.Equal(t, start+100, next)
+
+	_, err = sequence.NextBatch(ctx, "order", 0)
+	assert.Error(t, err)
+}
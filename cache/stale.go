@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// staleValuePrefix GetStale 写入值的信封前缀，以 NUL 字节开头与普通 Set 写入的文本值区分
+const staleValuePrefix = "\x00apc-stale:"
+
+// GetStale 带软/硬过期的读取：值写入时记录软过期时间（now+ttl），Redis 中保留 ttl+staleTTL 作为硬过期，
+// 未过软过期时直接返回；过软过期但未过硬过期时返回旧值并标记 stale，同时异步执行 loader 刷新（同一 key 同时只刷新一次）；
+// 硬过期后或 Redis 读取失败时同步执行 loader，此时 loader 失败才返回错误；软过期判断使用 RedisClient 的时钟
+// 值以带前缀的信封格式存储，key 中已有非 GetStale 写入的值时视为未命中，执行 loader 并覆盖
+func (r *RedisClient) GetStale(ctx context.Context, key string, ttl, staleTTL time.Duration, loader func() (string, error)) (string, bool, error) {
+	raw, err := r.client.Get(ctx, key).Result()
+	if err == nil {
+		softExpiry, value, ok := decodeStaleValue(raw)
+		if ok {
+			if r.clock.Now().UnixMilli() < softExpiry {
+				return value, false, nil
+			}
+			r.refreshStale(ctx, key, ttl, staleTTL, loader)
+			return value, true, nil
+		}
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		// Redis 不可用时退化为直接加载
+		value, loadErr := loader()
+		if loadErr != nil {
+			return "", false, fmt.Errorf("cache: get stale %q: %w", r.maskKey(key), errors.Join(err, loadErr))
+		}
+		return value, false, nil
+	}
+	value, err := loader()
+	if err != nil {
+		return "", false, fmt.Errorf("cache: get stale %q: load: %w", r.maskKey(key), err)
+	}
+	if err := r.setStale(ctx, key, value, ttl, staleTTL); err != nil {
+		return value, false, err
+	}
+	return value, false, nil
+}
+
+// refreshStale 异步执行 loader 并写回，使用不随调用方取消的 context，同一 key 已在刷新时跳过
+func (r *RedisClient) refreshStale(ctx context.Context, key string, ttl, staleTTL time.Duration, loader func() (string, error)) {
+	if _, loading := r.staleRefreshing.LoadOrStore(key, struct{}{}); loading {
+		return
+	}
+	refreshCtx := context.WithoutCancel(ctx)
+	go func() {
+		defer r.staleRefreshing.Delete(key)
+		value, err := loader()
+		if err != nil {
+			return
+		}
+		_ = r.setStale(refreshCtx, key, value, ttl, staleTTL)
+	}()
+}
+
+// setStale 以 "信封前缀 软过期毫秒时间戳:值" 格式写入，Redis 过期时间为 ttl+staleTTL
+func (r *RedisClient) setStale(ctx context.Context, key, value string, ttl, staleTTL time.Duration) error {
+	softExpiry := r.clock.Now().Add(ttl).UnixMilli()
+	payload := staleValuePrefix + strconv.FormatInt(softExpiry, 10) + ":" + value
+	if err := r.client.Set(ctx, key, payload, ttl+staleTTL).Err(); err != nil {
+		return r.errorf("cache: set stale %q: %w", r.maskKey(key), err)
+	}
+	return nil
+}
+
+// decodeStaleValue 解析 setStale 写入的值，缺少信封前缀或格式不符时 ok 为 false
+func decodeStaleValue(raw string) (int64, string, bool) {
+	payload, enveloped := strings.CutPrefix(raw, staleValuePrefix)
+	if !enveloped {
+		return 0, "", false
+	}
+	expiry, value, found := strings.Cut(payload, ":")
+	if !found {
+		return 0, "", false
+	}
+	softExpiry, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return softExpiry, value, true
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedisClientGetStale 验证超过软过期后返回旧值并标记 stale，同时触发异步刷新，刷新完成后返回新值
+func TestRedisClientGetStale(t *testing.T) {
+	base, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	redisClient := NewRedisClient(base.client, WithClock(clock))
+	ctx := context.Background()
+	key := "test_get_stale"
+
+	value, stale, err := redisClient.GetStale(ctx, key, time.Minute, time.Hour, func() (string, error) { return "v1", nil })
+	assert.Nil(t, err)
+	assert.False(t, stale)
+	assert.Equal(t, "v1", value)
+
+	value, stale, err = redisClient.GetStale(ctx, key, time.Minute, time.Hour, func() (string, error) {
+		t.Error("loader should not run before soft expiry")
+		return "", nil
+	})
+	assert.Nil(t, err)
+	assert.False(t, stale)
+	assert.Equal(t, "v1", value)
+
+	clock.Advance(2 * time.Minute)
+	refreshed := make(chan struct{})
+	value, stale, err = redisClient.GetStale(ctx, key, time.Minute, time.Hour, func() (string, error) {
+		defer close(refreshed)
+		return "v2", nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, stale)
+	assert.Equal(t, "v1", value)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("stale read should trigger an async refresh")
+	}
+	assert.Eventually(t, func() bool {
+		value, stale, err := redisClient.GetStale(ctx, key, time.Minute, time.Hour, func() (string, error) {
+			return "", errors.New("unexpected load")
+		})
+		return err == nil && !stale && value == "v2"
+	}, time.Second, 10*time.Millisecond)
+
+	_, _, err = redisClient.GetStale(ctx, "test_get_stale_missing", time.Minute, time.Hour, func() (string, error) {
+		return "", errors.New("source down")
+	})
+	assert.ErrorContains(t, err, "source down")
+}
+
+// TestRedisClientGetStalePlainValue 验证普通 Set 写入的值（即使形如 "123:abc"）不会被误解析为软过期信封，按未命中处理
+func TestRedisClientGetStalePlainValue(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_get_stale_plain"
+
+	assert.Nil(t, redisClient.Set(ctx, key, "99999999999999:abc", 0))
+	loaded := false
+	value, stale, err := redisClient.GetStale(ctx, key, time.Minute, time.Hour, func() (string, error) {
+		loaded = true
+		return "fresh", nil
+	})
+	assert.Nil(t, err)
+	assert.False(t, stale)
+	assert.Equal(t, "fresh", value)
+	assert.True(t, loaded, "plain values should be treated as a miss")
+
+	value, _, err = redisClient.GetStale(ctx, key, time.Minute, time.Hour, func() (string, error) {
+		return "", errors.New("unexpected load")
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "fresh", value)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// tagKeyPrefix 标签集合 key 前缀，集合成员为关联该标签的缓存 key
+	tagKeyPrefix = "cache:tag:"
+	// tagInvalidateBatchSize InvalidateTag 每批管道提交的 key 数量
+	tagInvalidateBatchSize = 500
+)
+
+// tagAddScript 将 key 加入标签集合并保证集合的过期时间不早于缓存值：
+// 缓存值不过期时集合同样不过期，集合为新建或剩余时间短于 ttl 时延长到 ttl，已永久保留的集合保持不变；
+// 脚本只访问 KEYS[1]，可在集群模式下执行
+const tagAddScript = `
+	local current = redis.call("PTTL", KEYS[1])
+	redis.call("SADD", KEYS[1], ARGV[1])
+	local ttl = tonumber(ARGV[2])
+	if ttl <= 0 then
+		redis.call("PERSIST", KEYS[1])
+	elseif current == -2 or (current >= 0 and current < ttl) then
+		redis.call("PEXPIRE", KEYS[1], ttl)
+	end
+	return 1
+`
+
+// SetTagged 写入缓存值并将 key 记录到每个标签集合中，用于按标签批量失效
+// 标签集合的过期时间随每次写入延长到不早于缓存值的过期时间，避免集合中长期堆积已过期的 key
+func (r *RedisClient) SetTagged(ctx context.Context, key string, val any, ttl time.Duration, tags ...string) error {
+	tagTTL := ttl.Milliseconds()
+	if ttl > 0 && tagTTL == 0 {
+		tagTTL = 1
+	}
+	pipe := r.client.Pipeline()
+	pipe.Set(ctx, key, val, ttl)
+	for _, tag := range tags {
+		pipe.Eval(ctx, tagAddScript, []string{tagKeyPrefix + tag}, key, tagTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return r.errorf("cache: set tagged %q: %w", r.maskKey(key), err)
+	}
+	return nil
+}
+
+// InvalidateTag 删除标签关联的所有 key，返回实际删除的缓存 key 数量
+// 先读取标签集合成员，再按批通过管道逐个 UNLINK 并从集合中移除，集合清空后由 Redis 自动删除；
+// 每个命令只涉及单个 key，可在集群模式下执行，读取后新写入同一标签的 key 保留在集合中，不会丢失关联
+func (r *RedisClient) InvalidateTag(ctx context.Context, tag string) (int64, error) {
+	tagKey := tagKeyPrefix + tag
+	members, err := r.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return 0, r.errorf("cache: invalidate tag %q: %w", r.maskKey(tag), err)
+	}
+	var removed int64
+	for start := 0; start < len(members); start += tagInvalidateBatchSize {
+		batch := members[start:min(start+tagInvalidateBatchSize, len(members))]
+		pipe := r.client.Pipeline()
+		unlinks := make([]*redis.IntCmd, len(batch))
+		processed := make([]interface{}, len(batch))
+		for i, key := range batch {
+			unlinks[i] = pipe.Unlink(ctx, key)
+			processed[i] = key
+		}
+		pipe.SRem(ctx, tagKey, processed...)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return removed, r.errorf("cache: invalidate tag %q: %w", r.maskKey(tag), err)
+		}
+		for _, cmd := range unlinks {
+			removed += cmd.Val()
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInvalidateTag 验证按标签失效删除所有关联 key，不影响其他标签的 key
+func TestInvalidateTag(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+	keys := []string{"test_tag_profile", "test_tag_orders", "test_tag_settings", "test_tag_other"}
+	defer func() {
+		_ = redisClient.Del(ctx, append(keys, tagKeyPrefix+"user:1", tagKeyPrefix+"user:2")...)
+	}()
+
+	for _, key := range keys[:3] {
+		assert.Nil(t, redisClient.SetTagged(ctx, key, "v", time.Minute, "user:1"))
+	}
+	assert.Nil(t, redisClient.SetTagged(ctx, keys[3], "v", time.Minute, "user:2"))
+
+	removed, err := redisClient.InvalidateTag(ctx, "user:1")
+	assert.Nil(t, err, "Should not return error while invalidating tag")
+	assert.Equal(t, int64(3), removed, "All tagged keys should be removed")
+
+	count, err := redisClient.Exists(ctx, keys[:3]...)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count, "Tagged keys should no longer exist")
+	count, err = redisClient.Exists(ctx, keys[3], tagKeyPrefix+"user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count, "Other tag's key should survive and the tag set should be cleaned")
+}
+
+// TestSetTaggedTagTTL 验证标签集合的过期时间随写入延长到不早于缓存值，缓存值不过期时集合同样不过期
+func TestSetTaggedTagTTL(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	tagKey := tagKeyPrefix + "ttl"
+
+	assert.Nil(t, redisClient.SetTagged(ctx, "test_tag_ttl:a", "v", time.Minute, "ttl"))
+	pttl, err := redisClient.PTTL(ctx, tagKey)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(time.Minute), float64(pttl), float64(time.Second))
+
+	assert.Nil(t, redisClient.SetTagged(ctx, "test_tag_ttl:b", "v", 10*time.Second, "ttl"))
+	pttl, err = redisClient.PTTL(ctx, tagKey)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(time.Minute), float64(pttl), float64(time.Second), "shorter ttl should not shrink the tag set")
+
+	assert.Nil(t, redisClient.SetTagged(ctx, "test_tag_ttl:c", "v", time.Hour, "ttl"))
+	pttl, err = redisClient.PTTL(ctx, tagKey)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(time.Hour), float64(pttl), float64(time.Second), "longer ttl should extend the tag set")
+
+	assert.Nil(t, redisClient.SetTagged(ctx, "test_tag_ttl:d", "v", 0, "ttl"))
+	assert.Nil(t, redisClient.SetTagged(ctx, "test_tag_ttl:e", "v", time.Minute, "ttl"))
+	pttl, err = redisClient.PTTL(ctx, tagKey)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(-1), pttl, "tag set of a persistent value should not expire")
+}
+
+// TestInvalidateTagBatches 验证成员数超过单批数量时分批删除全部 key 并清除标签集合
+func TestInvalidateTagBatches(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	total := tagInvalidateBatchSize*2 + 7
+	for i := 0; i < total; i++ {
+		assert.Nil(t, redisClient.SetTagged(ctx, fmt.Sprintf("test_tag_batch:%d", i), "v", time.Minute, "batch"))
+	}
+	_, err = redisClient.client.SAdd(ctx, tagKeyPrefix+"batch", "test_tag_batch:missing").Result()
+	assert.Nil(t, err)
+
+	removed, err := redisClient.InvalidateTag(ctx, "batch")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(total), removed, "missing members should not be counted")
+	count, err := redisClient.Exists(ctx, tagKeyPrefix+"batch", "test_tag_batch:0", fmt.Sprintf("test_tag_batch:%d", total-1))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewTestClient 启动进程内 miniredis 并返回连接它的 RedisClient，供无 Redis 环境的测试使用
+// cleanup 关闭客户端和内存服务；miniredis 支持 Lua 脚本，但 key 的 TTL 不会随真实时间流逝
+func NewTestClient() (*RedisClient, func(), error) {
+	server, err := miniredis.Run()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cache: start miniredis: %w", err)
+	}
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cleanup := func() {
+		_ = client.Close()
+		server.Close()
+	}
+	return NewRedisClient(client), cleanup, nil
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTestClientLua 验证基于 Lua 脚本的 CAS 与分布式锁可在 miniredis 上运行
+func TestNewTestClientLua(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Nil(t, redisClient.Set(ctx, "test_harness_cas", "v1", time.Minute))
+	swapped, err := redisClient.CAS(ctx, "test_harness_cas", "v1", "v2", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, swapped)
+
+	lock := NewRedisLock(redisClient.client, "test_harness_lock", time.Second)
+	locked, err := lock.Acquire(ctx)
+	assert.Nil(t, err)
+	assert.True(t, locked)
+	assert.Nil(t, lock.Release(ctx))
+}
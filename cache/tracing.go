@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	apctracing "github.com/ethereal3x/apc/tracing"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracing 为每次 Redis 调用创建子 span（如 redis.get），仅在传入的 context 已有 span 时生效，
+// span 记录 db.statement（命令与 key，不含写入值）和 db.redis.key 属性，key 经 WithKeyMasker 脱敏，失败时通过 tracing.RecordError 记录错误。
+// 钩子注册在共享连接池的客户端副本上，只作用于当前 RedisClient，共用底层客户端但未启用 WithTracing 的组件不受影响；
+// context 中没有 span 时钩子直接透传，未接入链路的调用几乎无开销
+func WithTracing() RedisClientOption {
+	return func(r *RedisClient) {
+		r.client = r.scopedClient()
+		r.client.AddHook(tracingHook{client: r})
+	}
+}
+
+// tracingHook 基于 go-redis 钩子的 OpenTelemetry 埋点，运行时通过 client 读取 key 脱敏配置
+type tracingHook struct {
+	client *RedisClient
+}
+
+// tracingActiveKey 标记当前调用已由 tracingHook 创建 span，重复启用 WithTracing 导致钩子叠加时外层之外的钩子直接透传
+type tracingActiveKey struct{}
+
+// skipTracing 判断调用是否无需创建 span：context 没有 span，或外层 tracingHook 已创建
+func skipTracing(ctx context.Context) bool {
+	return !trace.SpanFromContext(ctx).SpanContext().IsValid() || ctx.Value(tracingActiveKey{}) != nil
+}
+
+// DialHook 建连不创建 span
+func (tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 为单条命令创建子 span
+func (hook tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if skipTracing(ctx) {
+			return next(ctx, cmd)
+		}
+		ctx, span := apctracing.Start(context.WithValue(ctx, tracingActiveKey{}, struct{}{}), "redis."+cmd.Name())
+		defer span.End()
+		key := commandKey(cmd)
+		if key != "" {
+			key = hook.client.maskKey(key)
+		}
+		span.SetAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.statement", strings.TrimSpace(strings.ToUpper(cmd.Name())+" "+key)),
+			attribute.String("db.redis.key", key),
+		)
+		err := next(ctx, cmd)
+		recordRedisError(ctx, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook 为整个管道创建一个子 span
+func (tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if skipTracing(ctx) {
+			return next(ctx, cmds)
+		}
+		ctx, span := apctracing.Start(context.WithValue(ctx, tracingActiveKey{}, struct{}{}), "redis.pipeline")
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.Int("db.redis.num_cmd", len(cmds)),
+		)
+		err := next(ctx, cmds)
+		recordRedisError(ctx, err)
+		return err
+	}
+}
+
+// recordRedisError 记录 Redis 错误，key 不存在不视为错误
+func recordRedisError(ctx context.Context, err error) {
+	if err != nil && !errors.Is(err, redis.Nil) {
+		apctracing.RecordError(ctx, err)
+	}
+}
+
+// commandKey 提取命令操作的首个 key，EVAL/EVALSHA 取 KEYS[1]
+func commandKey(cmd redis.Cmder) string {
+	args := cmd.Args()
+	keyIndex := 1
+	switch cmd.Name() {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro":
+		keyIndex = 3
+		if len(args) < 3 || fmt.Sprint(args[2]) == "0" {
+			return ""
+		}
+	}
+	if len(args) <= keyIndex {
+		return ""
+	}
+	return fmt.Sprint(args[keyIndex])
+}
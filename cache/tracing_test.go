@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	apctracing "github.com/ethereal3x/apc/tracing"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useCacheSpanRecorder 安装内存 span 记录器作为全局 provider，测试结束后恢复
+func useCacheSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+// spanAttribute 查找 span 上指定属性的值
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// TestRedisClientWithTracing 验证存在父 span 时为命令创建携带 key 属性的子 span，并记录命令错误
+func TestRedisClientWithTracing(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	recorder := useCacheSpanRecorder(t)
+	redisClient := NewRedisClient(client, WithTracing())
+	ctx := context.Background()
+	defer func() {
+		_ = redisClient.Del(ctx, "test_tracing_key")
+	}()
+
+	// 无父 span 时不创建 span
+	assert.Nil(t, redisClient.Set(ctx, "test_tracing_key", "not-a-number", time.Minute))
+	assert.Empty(t, recorder.Ended(), "No span should be created without a parent span")
+
+	parentCtx, parent := apctracing.Start(ctx, "handler")
+	_, err := redisClient.Get(parentCtx, "test_tracing_key")
+	assert.Nil(t, err)
+	_, err = redisClient.Incr(parentCtx, "test_tracing_key")
+	assert.Error(t, err, "INCR on a non-integer value should fail")
+	parent.End()
+
+	ended := recorder.Ended()
+	assert.Len(t, ended, 3)
+	getSpan, incrSpan := ended[0], ended[1]
+	assert.Equal(t, "redis.get", getSpan.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), getSpan.Parent().SpanID(), "Command span should be a child of the handler span")
+	key, ok := spanAttribute(getSpan, "db.redis.key")
+	assert.True(t, ok, "Span should carry the key attribute")
+	assert.Equal(t, "test_tracing_key", key.AsString())
+	statement, _ := spanAttribute(getSpan, "db.statement")
+	assert.Equal(t, "GET test_tracing_key", statement.AsString())
+
+	assert.Equal(t, "redis.incr", incrSpan.Name())
+	assert.Equal(t, codes.Error, incrSpan.Status().Code, "Command error should be recorded on the span")
+}
+
+// TestRedisClientWithTracingSharedClient 验证钩子只作用于启用 WithTracing 的 RedisClient，共用底层客户端的其他实例不产生 span，
+// 重复启用时每条命令只产生一个 span，且 key 属性经过脱敏
+func TestRedisClientWithTracingSharedClient(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	recorder := useCacheSpanRecorder(t)
+	traced := NewRedisClient(client, WithTracing(), WithTracing(), WithKeyMasker(func(string) string { return "masked" }))
+	untraced := NewRedisClient(client)
+
+	parentCtx, parent := apctracing.Start(context.Background(), "handler")
+	assert.Nil(t, traced.Set(parentCtx, "test_tracing_shared", "v", time.Minute))
+	_, err := untraced.Get(parentCtx, "test_tracing_shared")
+	assert.Nil(t, err)
+	assert.Nil(t, client.Get(parentCtx, "test_tracing_shared").Err())
+	parent.End()
+
+	ended := recorder.Ended()
+	names := make([]string, 0, len(ended))
+	for _, span := range ended {
+		names = append(names, span.Name())
+	}
+	assert.Equal(t, []string{"redis.set", "handler"}, names)
+	key, _ := spanAttribute(ended[0], "db.redis.key")
+	assert.Equal(t, "masked", key.AsString())
+	statement, _ := spanAttribute(ended[0], "db.statement")
+	assert.Equal(t, "SET masked", statement.AsString())
+}
+
+// TestRedisLockWithLockTracing 验证获取与释放锁分别创建携带锁名、等待时长和结果属性的 lock.acquire/lock.release span
+func TestRedisLockWithLockTracing(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	recorder := useCacheSpanRecorder(t)
+	ctx := context.Background()
+	lockName := "test_lock_tracing"
+
+	lock := NewRedisLock(redisClient.client, lockName, time.Second, WithLockTracing(NewOtelLockTracer()))
+	locked, err := lock.Acquire(ctx)
+	assert.Nil(t, err)
+	assert.True(t, locked)
+	contender := NewRedisLock(redisClient.client, lockName, time.Second, WithLockTracing(NewOtelLockTracer()))
+	locked, err = contender.AcquireWithTimeout(ctx, 20*time.Millisecond)
+	assert.Nil(t, err)
+	assert.False(t, locked)
+	assert.Nil(t, lock.Release(ctx))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	expected := []struct {
+		name    string
+		outcome string
+	}{
+		{"lock.acquire", lockOutcomeAcquired},
+		{"lock.acquire", lockOutcomeNotAcquired},
+		{"lock.release", lockOutcomeReleased},
+	}
+	for i, span := range spans {
+		assert.Equal(t, expected[i].name, span.Name())
+		name, ok := spanAttribute(span, "lock.name")
+		assert.True(t, ok)
+		assert.Equal(t, lockName, name.AsString())
+		outcome, ok := spanAttribute(span, "lock.outcome")
+		assert.True(t, ok)
+		assert.Equal(t, expected[i].outcome, outcome.AsString())
+		_, ok = spanAttribute(span, "lock.wait_ms")
+		assert.True(t, ok)
+	}
+	attempts, ok := spanAttribute(spans[1], "lock.attempts")
+	assert.True(t, ok)
+	assert.GreaterOrEqual(t, attempts.AsInt64(), int64(1))
+}
+
+// TestRedisLockWithoutTracing 验证未启用 WithLockTracing 或传入 nil tracer 时不创建 span
+func TestRedisLockWithoutTracing(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	recorder := useCacheSpanRecorder(t)
+	ctx := context.Background()
+
+	for _, lock := range []*RedisLock{
+		NewRedisLock(redisClient.client, "test_lock_no_tracing", time.Second),
+		NewRedisLock(redisClient.client, "test_lock_no_tracing", time.Second, WithLockTracing(nil)),
+	} {
+		locked, err := lock.Acquire(ctx)
+		assert.Nil(t, err)
+		assert.True(t, locked)
+		assert.Nil(t, lock.Release(ctx))
+	}
+	assert.Empty(t, recorder.Ended())
+}
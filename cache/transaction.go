@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultTxMaxRetries = 3
+
+// Tx 乐观事务句柄，读操作在 WATCH 下立即执行，写操作先排队，fn 成功返回后在 MULTI/EXEC 中原子提交
+type Tx struct {
+	tx     *redis.Tx
+	client *RedisClient
+	ops    []func(ctx context.Context, pipe redis.Pipeliner)
+}
+
+// WithTxMaxRetries 设置 Transaction 因 WATCH 的 key 被并发修改而失败时的最大重试次数（默认 3），0 表示不重试
+func WithTxMaxRetries(retries int) RedisClientOption {
+	return func(r *RedisClient) {
+		if retries >= 0 {
+			r.txMaxRetries = retries
+		}
+	}
+}
+
+// Transaction 以 WATCH/MULTI/EXEC 执行乐观事务，fn 中基于读取结果排队写操作
+// watchKeys 在提交前被其他客户端修改时 EXEC 失败并重新执行 fn，超过重试次数返回包装 redis.TxFailedErr 的错误；
+// fn 返回的错误原样返回且不提交任何写操作
+func (r *RedisClient) Transaction(ctx context.Context, fn func(tx *Tx) error, watchKeys ...string) error {
+	for attempt := 0; ; attempt++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			handle := &Tx{tx: tx, client: r}
+			if err := fn(handle); err != nil {
+				return &txFnError{err: err}
+			}
+			return handle.exec(ctx)
+		}, watchKeys...)
+		var fnErr *txFnError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &fnErr):
+			return fnErr.err
+		case errors.Is(err, redis.TxFailedErr) && attempt < r.txMaxRetries:
+			continue
+		default:
+			return r.errorf("cache: transaction %v: %w", r.maskKeys(watchKeys), err)
+		}
+	}
+}
+
+// txFnError 区分业务函数错误与 Redis 错误，避免业务错误被当作事务冲突重试
+type txFnError struct {
+	err error
+}
+
+func (e *txFnError) Error() string {
+	return e.err.Error()
+}
+
+// exec 在 MULTI/EXEC 中提交排队的写操作，没有写操作时不发送 EXEC
+func (tx *Tx) exec(ctx context.Context) error {
+	if len(tx.ops) == 0 {
+		return nil
+	}
+	_, err := tx.tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, op := range tx.ops {
+			op(ctx, pipe)
+		}
+		return nil
+	})
+	return err
+}
+
+// Get 在 WATCH 下读取 key，key 不存在时返回空字符串
+func (tx *Tx) Get(ctx context.Context, key string) (string, error) {
+	val, err := tx.tx.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", tx.client.errorf("cache: tx get %q: %w", tx.client.maskKey(key), err)
+	}
+	return val, nil
+}
+
+// HGet 在 WATCH 下读取哈希字段，字段不存在时返回空字符串
+func (tx *Tx) HGet(ctx context.Context, key, field string) (string, error) {
+	val, err := tx.tx.HGet(ctx, key, field).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", tx.client.errorf("cache: tx hget %q:%q: %w", tx.client.maskKey(key), field, err)
+	}
+	return val, nil
+}
+
+// Exists 在 WATCH 下检查 key 是否存在
+func (tx *Tx) Exists(ctx context.Context, keys ...string) (int64, error) {
+	count, err := tx.tx.Exists(ctx, keys...).Result()
+	if err != nil {
+		return 0, tx.client.errorf("cache: tx exists %v: %w", tx.client.maskKeys(keys), err)
+	}
+	return count, nil
+}
+
+// Set 排队设置 key 的值
+func (tx *Tx) Set(key string, val any, ttl time.Duration) {
+	tx.ops = append(tx.ops, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.Set(ctx, key, val, ttl)
+	})
+}
+
+// Del 排队删除 key
+func (tx *Tx) Del(keys ...string) {
+	tx.ops = append(tx.ops, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.Del(ctx, keys...)
+	})
+}
+
+// IncrBy 排队对 key 增加 step
+func (tx *Tx) IncrBy(key string, step int64) {
+	tx.ops = append(tx.ops, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.IncrBy(ctx, key, step)
+	})
+}
+
+// HSet 排队设置哈希字段
+func (tx *Tx) HSet(key string, values ...interface{}) {
+	tx.ops = append(tx.ops, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.HSet(ctx, key, values...)
+	})
+}
+
+// Expire 排队设置 key 的过期时间
+func (tx *Tx) Expire(key string, ttl time.Duration) {
+	tx.ops = append(tx.ops, func(ctx context.Context, pipe redis.Pipeliner) {
+		pipe.Expire(ctx, key, ttl)
+	})
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedisClientTransaction 验证 check-then-set 事务在 WATCH 的 key 被并发修改后重试一次并基于最新值提交
+func TestRedisClientTransaction(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+	key := "test_tx_balance"
+	assert.Nil(t, redisClient.Set(ctx, key, "100", 0))
+
+	attempts := 0
+	err = redisClient.Transaction(ctx, func(tx *Tx) error {
+		attempts++
+		raw, err := tx.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		balance, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// 模拟其他客户端在读取之后、提交之前修改了余额
+			assert.Nil(t, redisClient.Set(ctx, key, "50", 0))
+		}
+		if balance < 30 {
+			return errors.New("insufficient balance")
+		}
+		tx.Set(key, balance-30, 0)
+		return nil
+	}, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts, "transaction should retry once after conflict")
+	got, err := redisClient.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, "20", got)
+
+	err = redisClient.Transaction(ctx, func(tx *Tx) error {
+		raw, err := tx.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if balance, _ := strconv.Atoi(raw); balance < 30 {
+			return errors.New("insufficient balance")
+		}
+		tx.Del(key)
+		return nil
+	}, key)
+	assert.EqualError(t, err, "insufficient balance")
+	got, err = redisClient.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, "20", got, "failed fn should not commit queued writes")
+}
+
+// TestRedisClientTransactionRetriesExhausted 验证持续冲突时按配置次数重试后返回 redis.TxFailedErr
+func TestRedisClientTransactionRetriesExhausted(t *testing.T) {
+	base, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	redisClient := NewRedisClient(base.client, WithTxMaxRetries(1))
+	ctx := context.Background()
+	key := "test_tx_conflict"
+
+	attempts := 0
+	err = redisClient.Transaction(ctx, func(tx *Tx) error {
+		attempts++
+		assert.Nil(t, redisClient.Set(ctx, key, attempts, time.Minute))
+		tx.IncrBy(key, 1)
+		return nil
+	}, key)
+	assert.ErrorIs(t, err, redis.TxFailedErr)
+	assert.Equal(t, 2, attempts)
+}
@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// WithTTLJitter 为 Set 与 SetMany 写入的过期时间追加 [0, maxJitter] 内的随机抖动，
+// 避免批量预热的 key 在同一时刻集中过期引发缓存击穿；ttl 为 0（不过期）或 KeepTTL 时保持不变，maxJitter 不大于 0 时不启用
+func WithTTLJitter(maxJitter time.Duration) RedisClientOption {
+	return func(r *RedisClient) {
+		if maxJitter > 0 {
+			r.ttlJitter = maxJitter
+		}
+	}
+}
+
+// jitterTTL 按 WithTTLJitter 配置为正的过期时间追加随机抖动
+func (r *RedisClient) jitterTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || r.ttlJitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int64N(int64(r.ttlJitter)+1))
+}
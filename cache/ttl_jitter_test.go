@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithTTLJitter 验证 Set/SetMany 写入的过期时间分散在 [ttl, ttl+maxJitter] 内，不过期的 key 保持不变
+func TestWithTTLJitter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	redisClient := NewRedisClient(client, WithTTLJitter(time.Minute))
+	ctx := context.Background()
+
+	const ttl = 10 * time.Minute
+	items := make(map[string]Item, 100)
+	keys := make([]string, 0, 200)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("test_ttl_jitter:many:%d", i)
+		items[key] = Item{Value: i, TTL: ttl}
+		keys = append(keys, key)
+	}
+	assert.Nil(t, redisClient.SetMany(ctx, items))
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("test_ttl_jitter:single:%d", i)
+		assert.Nil(t, redisClient.Set(ctx, key, i, ttl))
+		keys = append(keys, key)
+	}
+
+	distinct := make(map[time.Duration]struct{}, len(keys))
+	for _, key := range keys {
+		pttl, err := redisClient.PTTL(ctx, key)
+		assert.Nil(t, err)
+		assert.GreaterOrEqual(t, pttl, ttl, key)
+		assert.LessOrEqual(t, pttl, ttl+time.Minute, key)
+		distinct[pttl] = struct{}{}
+	}
+	assert.Greater(t, len(distinct), len(keys)/2, "ttls should be spread across the jitter window")
+
+	assert.Nil(t, redisClient.Set(ctx, "test_ttl_jitter:persistent", "v", 0))
+	persistent, err := redisClient.PTTL(ctx, "test_ttl_jitter:persistent")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(-1), persistent)
+}
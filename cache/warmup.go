@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// defaultWarmupBatch Warmup 单个管道写入的 key 数量
+const defaultWarmupBatch = 100
+
+// WarmupOption 缓存预热配置选项
+type WarmupOption func(*warmupOptions)
+
+// warmupOptions 汇总缓存预热可选项
+type warmupOptions struct {
+	batchSize int
+	progress  func(loaded, total int)
+}
+
+// WithWarmupBatchSize 设置单个管道写入的 key 数量（默认 100）
+func WithWarmupBatchSize(batchSize int) WarmupOption {
+	return func(options *warmupOptions) {
+		if batchSize > 0 {
+			options.batchSize = batchSize
+		}
+	}
+}
+
+// WithWarmupProgress 设置进度回调，每个批次写入成功后以已写入数量和总数调用，回调串行执行
+func WithWarmupProgress(progress func(loaded, total int)) WarmupOption {
+	return func(options *warmupOptions) {
+		options.progress = progress
+	}
+}
+
+// Warmup 将 items 分批以管道写入，最多 concurrency 个批次并发执行（<= 0 时为 1），用于发布时预热缓存避免冷启动击穿
+// ctx 取消或任一批次失败后不再派发新批次，返回已写入数量与错误，已写入的数据不回滚
+func (r *RedisClient) Warmup(ctx context.Context, items map[string]Item, concurrency int, opts ...WarmupOption) (int, error) {
+	options := warmupOptions{batchSize: defaultWarmupBatch}
+	for _, option := range opts {
+		option(&options)
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	batches := make(chan map[string]Item)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		loaded   int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := r.SetMany(ctx, batch); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				mu.Lock()
+				loaded += len(batch)
+				if options.progress != nil {
+					options.progress(loaded, len(items))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// 派发批次，ctx 取消后停止
+	batch := make(map[string]Item, options.batchSize)
+	dispatch := func() bool {
+		select {
+		case batches <- batch:
+			batch = make(map[string]Item, options.batchSize)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	dispatched := true
+	for key, item := range items {
+		batch[key] = item
+		if len(batch) == options.batchSize {
+			if dispatched = dispatch(); !dispatched {
+				break
+			}
+		}
+	}
+	if dispatched && len(batch) > 0 {
+		dispatch()
+	}
+	close(batches)
+	wg.Wait()
+
+	if firstErr != nil {
+		return loaded, fmt.Errorf("cache: warmup: %w", firstErr)
+	}
+	if loaded < len(items) && ctx.Err() != nil {
+		return loaded, fmt.Errorf("cache: warmup: %w", ctx.Err())
+	}
+	return loaded, nil
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedisClientWarmup 验证 8 并发预热 1000 个 key 后全部存在，进度回调报告到总数
+func TestRedisClientWarmup(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx := context.Background()
+
+	items := make(map[string]Item, 1000)
+	keys := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("test_warmup_%d", i)
+		items[key] = Item{Value: i, TTL: time.Minute}
+		keys = append(keys, key)
+	}
+	var lastProgress int
+	loaded, err := redisClient.Warmup(ctx, items, 8, WithWarmupProgress(func(loaded, total int) {
+		assert.Equal(t, 1000, total)
+		assert.Greater(t, loaded, lastProgress)
+		lastProgress = loaded
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, loaded)
+	assert.Equal(t, 1000, lastProgress)
+	count, err := redisClient.Exists(ctx, keys...)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), count)
+}
+
+// TestRedisClientWarmupCancel 验证预热过程中取消 context 后提前停止并返回已写入数量
+func TestRedisClientWarmupCancel(t *testing.T) {
+	redisClient, cleanup, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("start test redis: %v", err)
+	}
+	defer cleanup()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items := make(map[string]Item, 1000)
+	for i := 0; i < 1000; i++ {
+		items[fmt.Sprintf("test_warmup_cancel_%d", i)] = Item{Value: i, TTL: time.Minute}
+	}
+	loaded, err := redisClient.Warmup(ctx, items, 2, WithWarmupBatchSize(10), WithWarmupProgress(func(loaded, total int) {
+		if loaded >= 50 {
+			cancel()
+		}
+	}))
+	assert.True(t, errors.Is(err, context.Canceled), "err = %v", err)
+	assert.GreaterOrEqual(t, loaded, 50)
+	assert.Less(t, loaded, 1000)
+	size, err := redisClient.DBSize(context.Background())
+	assert.Nil(t, err)
+	assert.Less(t, size, int64(1000), "remaining batches should not be written after cancel")
+}
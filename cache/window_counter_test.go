@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock 可手动推进的测试时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now 返回假时钟的当前时间
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+// Advance 将假时钟向前推进 d
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
+// TestWindowCounterRollover 验证通过假时钟推进时间后计数写入新窗口，Sum 汇总最近窗口的总数，无需真实等待
+func TestWindowCounterRollover(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	skipIfRedisUnavailable(t, client)
+	clock := &fakeClock{now: time.Unix(1_700_000_040, 0)}
+	counter := NewWindowCounter(NewRedisClient(client, WithClock(clock)))
+	ctx := context.Background()
+	name := "test_window_counter"
+	window := time.Minute
+
+	firstKey := counter.bucketKey(name, window, 0)
+	secondKey := counter.bucketKey(name, window, -1)
+	defer func() {
+		_ = client.Del(ctx, firstKey, secondKey).Err()
+	}()
+	_ = client.Del(ctx, firstKey, secondKey).Err()
+
+	for i := 0; i < 3; i++ {
+		_, err := counter.Incr(ctx, name, window)
+		assert.Nil(t, err)
+	}
+	ttl, err := client.PTTL(ctx, firstKey).Result()
+	assert.Nil(t, err)
+	assert.True(t, ttl > window && ttl <= 2*window, "bucket should expire after 2×window, got %v", ttl)
+
+	clock.Advance(window)
+	count, err := counter.Incr(ctx, name, window)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count, "new window should start from zero")
+	_, err = counter.Incr(ctx, name, window)
+	assert.Nil(t, err)
+
+	total, err := counter.Sum(ctx, name, window, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), total)
+
+	latest, err := counter.Sum(ctx, name, window, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), latest)
+}
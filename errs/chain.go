@@ -0,0 +1,27 @@
+package errs
+
+import "errors"
+
+// Chain 返回从 err 开始逐层 Unwrap 得到的完整错误链，仅沿单一 Unwrap() error 展开，errors.Join 等多分支错误不展开
+func Chain(err error) []error {
+	var chain []error
+	for err != nil {
+		chain = append(chain, err)
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
+
+// Cause 返回错误链中最深一层的非 BizError 根因，链中只有 BizError 时返回最深一层，err 为 nil 时返回 nil
+func Cause(err error) error {
+	chain := Chain(err)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if _, ok := chain[i].(*BizError); !ok {
+			return chain[i]
+		}
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain[len(chain)-1]
+}
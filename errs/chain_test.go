@@ -0,0 +1,39 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestChainAndCause 校验三层包装链的完整展开与根因提取
+func TestChainAndCause(t *testing.T) {
+	root := errors.New("connection refused")
+	middle := fmt.Errorf("query user: %w", root)
+	top := fmt.Errorf("load profile: %w", middle)
+
+	chain := Chain(top)
+	if len(chain) != 3 || chain[0] != top || chain[1] != middle || chain[2] != root {
+		t.Fatalf("unexpected chain: %v", chain)
+	}
+	if cause := Cause(top); cause != root {
+		t.Fatalf("expected root cause, got %v", cause)
+	}
+	if cause := Cause(root); cause != root {
+		t.Fatalf("expected unwrapped error as its own cause, got %v", cause)
+	}
+}
+
+// TestCauseOnlyBizError 校验链中只有 BizError 及 nil 输入的情况
+func TestCauseOnlyBizError(t *testing.T) {
+	wrapped := fmt.Errorf("handler: %w", ErrRedisRequest)
+	if cause := Cause(wrapped); cause != wrapped {
+		t.Fatalf("expected deepest non-BizError wrapper, got %v", cause)
+	}
+	if cause := Cause(ErrRedisRequest); cause != ErrRedisRequest {
+		t.Fatalf("expected BizError itself, got %v", cause)
+	}
+	if Cause(nil) != nil || Chain(nil) != nil {
+		t.Fatal("expected nil for nil error")
+	}
+}
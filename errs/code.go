@@ -1,16 +1,73 @@
 package errs
 
+import (
+	"fmt"
+	"sync"
+)
+
 // 通用业务错误码
 const (
 	// 系统级错误 100+
+	ERR_CODE_INTERNAL       ErrorCode = 100
 	ERR_CODE_REDIS_REQUEST  ErrorCode = 101
 	ERR_CODE_JSON_MARSHAL   ErrorCode = 102
 	ERR_CODE_JSON_UNMARSHAL ErrorCode = 103
+	ERR_CODE_CACHE_MISS     ErrorCode = 104
+
+	// 通用请求错误 400+
+	ERR_CODE_INVALID_ARGUMENT ErrorCode = 400
+	ERR_CODE_UNAUTHORIZED     ErrorCode = 401
+	ERR_CODE_NOT_FOUND        ErrorCode = 404
+	ERR_CODE_CONFLICT         ErrorCode = 409
 )
 
 // 预定义业务错误实例
 var (
-	ErrRedisRequest  = newBizError(ERR_CODE_REDIS_REQUEST, "Redis请求失败")
+	ErrInternal      = newBizError(ERR_CODE_INTERNAL, "服务内部错误")
+	ErrRedisRequest  = &BizError{Code: ERR_CODE_REDIS_REQUEST, Msg: "Redis请求失败", Retryable: true}
 	ErrJsonMarshal   = newBizError(ERR_CODE_JSON_MARSHAL, "Json压缩失败")
 	ErrJsonUnmarshal = newBizError(ERR_CODE_JSON_UNMARSHAL, "Json解压失败")
+	// ErrCacheMiss 缓存未命中属于预期情况，严重程度为 info
+	ErrCacheMiss = newBizError(ERR_CODE_CACHE_MISS, "缓存未命中").WithSeverity(SeverityInfo)
+)
+
+// 通用错误码匹配标记，配合 errors.Is 按错误码判断，如 errors.Is(err, ErrNotFoundMarker)；内部错误使用 ErrInternal
+var (
+	ErrInvalidArgumentMarker = newBizError(ERR_CODE_INVALID_ARGUMENT, "请求参数错误")
+	ErrUnauthorizedMarker    = newBizError(ERR_CODE_UNAUTHORIZED, "未授权")
+	ErrNotFoundMarker        = newBizError(ERR_CODE_NOT_FOUND, "资源不存在")
+	ErrConflictMarker        = newBizError(ERR_CODE_CONFLICT, "资源冲突")
 )
+
+var (
+	codeNamesMu sync.RWMutex
+	codeNames   = map[ErrorCode]string{
+		ERR_CODE_INTERNAL:         "INTERNAL",
+		ERR_CODE_REDIS_REQUEST:    "REDIS_REQUEST",
+		ERR_CODE_JSON_MARSHAL:     "JSON_MARSHAL",
+		ERR_CODE_JSON_UNMARSHAL:   "JSON_UNMARSHAL",
+		ERR_CODE_CACHE_MISS:       "CACHE_MISS",
+		ERR_CODE_INVALID_ARGUMENT: "INVALID_ARGUMENT",
+		ERR_CODE_UNAUTHORIZED:     "UNAUTHORIZED",
+		ERR_CODE_NOT_FOUND:        "NOT_FOUND",
+		ERR_CODE_CONFLICT:         "CONFLICT",
+	}
+)
+
+// RegisterCode 注册错误码的文本名称（如 USER_NOT_FOUND），重复注册时覆盖
+func RegisterCode(code ErrorCode, name string) {
+	codeNamesMu.Lock()
+	defer codeNamesMu.Unlock()
+	codeNames[code] = name
+}
+
+// String 返回错误码注册的文本名称，未注册时返回 UNKNOWN(code)
+func (code ErrorCode) String() string {
+	codeNamesMu.RLock()
+	name, ok := codeNames[code]
+	codeNamesMu.RUnlock()
+	if ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", int32(code))
+}
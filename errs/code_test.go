@@ -0,0 +1,50 @@
+package errs
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestErrorCodeString 校验已注册错误码返回注册名称，未注册错误码返回 UNKNOWN(code)
+func TestErrorCodeString(t *testing.T) {
+	const userNotFound ErrorCode = 40401
+	if got := userNotFound.String(); got != "UNKNOWN(40401)" {
+		t.Fatalf("unregistered code String() = %q", got)
+	}
+
+	RegisterCode(userNotFound, "USER_NOT_FOUND")
+	defer func() {
+		codeNamesMu.Lock()
+		delete(codeNames, userNotFound)
+		codeNamesMu.Unlock()
+	}()
+	if got := userNotFound.String(); got != "USER_NOT_FOUND" {
+		t.Fatalf("registered code String() = %q", got)
+	}
+	if got := fmt.Sprint(ERR_CODE_NOT_FOUND); got != "NOT_FOUND" {
+		t.Fatalf("builtin code String() = %q", got)
+	}
+}
+
+// TestBizErrorCodeText 校验 CodeText 优先使用显式 TextCode，并通过 SetErrMsg 写入响应
+func TestBizErrorCodeText(t *testing.T) {
+	if got := ErrNotFoundMarker.CodeText(); got != "NOT_FOUND" {
+		t.Fatalf("CodeText() = %q", got)
+	}
+	bizErr := &BizError{Code: 40402, TextCode: "ORDER_NOT_FOUND", Msg: "订单不存在"}
+	if got := bizErr.CodeText(); got != "ORDER_NOT_FOUND" {
+		t.Fatalf("CodeText() = %q", got)
+	}
+
+	rsp := &struct {
+		Code     int32
+		TextCode string
+		Message  string
+	}{}
+	if err := SetErrMsg(rsp, bizErr); err != nil {
+		t.Fatalf("SetErrMsg() error = %v", err)
+	}
+	if rsp.Code != 40402 || rsp.TextCode != "ORDER_NOT_FOUND" || rsp.Message != "订单不存在" {
+		t.Fatalf("unexpected reply: %+v", rsp)
+	}
+}
@@ -0,0 +1,44 @@
+package errs
+
+// InvalidArgument 创建参数错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func InvalidArgument(msg string, cause error) error {
+	return newWithCause(ErrInvalidArgumentMarker, msg, cause)
+}
+
+// Unauthorized 创建未授权错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func Unauthorized(msg string, cause error) error {
+	return newWithCause(ErrUnauthorizedMarker, msg, cause)
+}
+
+// NotFound 创建资源不存在错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func NotFound(msg string, cause error) error {
+	return newWithCause(ErrNotFoundMarker, msg, cause)
+}
+
+// Conflict 创建资源冲突错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func Conflict(msg string, cause error) error {
+	return newWithCause(ErrConflictMarker, msg, cause)
+}
+
+// Internal 创建服务内部错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func Internal(msg string, cause error) error {
+	return newWithCause(ErrInternal, msg, cause)
+}
+
+// CacheMiss 创建缓存未命中错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func CacheMiss(msg string, cause error) error {
+	return newWithCause(ErrCacheMiss, msg, cause)
+}
+
+// CacheFailure 创建可重试的 Redis 请求失败错误，msg 为空时使用默认消息，cause 可通过 errors.Unwrap 获取
+func CacheFailure(msg string, cause error) error {
+	return newWithCause(ErrRedisRequest, msg, cause)
+}
+
+// newWithCause 基于标记错误的错误码、可重试标记与严重程度创建包装 cause 的 BizError
+func newWithCause(marker *BizError, msg string, cause error) error {
+	if msg == "" {
+		msg = marker.Msg
+	}
+	return &BizError{Code: marker.Code, Msg: msg, Retryable: marker.Retryable, severity: marker.severity, cause: cause}
+}
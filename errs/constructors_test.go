@@ -0,0 +1,56 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestConstructorsCodeAndIs 校验各构造函数的错误码、默认消息、根因，以及 errors.Is 按错误码匹配
+func TestConstructorsCodeAndIs(t *testing.T) {
+	cause := errors.New("record not found")
+	tests := []struct {
+		name   string
+		build  func(string, error) error
+		code   ErrorCode
+		marker *BizError
+	}{
+		{"InvalidArgument", InvalidArgument, ERR_CODE_INVALID_ARGUMENT, ErrInvalidArgumentMarker},
+		{"Unauthorized", Unauthorized, ERR_CODE_UNAUTHORIZED, ErrUnauthorizedMarker},
+		{"NotFound", NotFound, ERR_CODE_NOT_FOUND, ErrNotFoundMarker},
+		{"Conflict", Conflict, ERR_CODE_CONFLICT, ErrConflictMarker},
+		{"Internal", Internal, ERR_CODE_INTERNAL, ErrInternal},
+		{"CacheMiss", CacheMiss, ERR_CODE_CACHE_MISS, ErrCacheMiss},
+		{"CacheFailure", CacheFailure, ERR_CODE_REDIS_REQUEST, ErrRedisRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("handler: %w", tt.build("custom message", cause))
+			var bizErr *BizError
+			if !errors.As(err, &bizErr) {
+				t.Fatalf("expected BizError, got %T", err)
+			}
+			if bizErr.Code != tt.code || bizErr.Msg != "custom message" {
+				t.Fatalf("unexpected code/msg: %d %q", bizErr.Code, bizErr.Msg)
+			}
+			if !errors.Is(err, tt.marker) {
+				t.Fatalf("expected errors.Is to match marker for code %d", tt.code)
+			}
+			if !errors.Is(err, cause) {
+				t.Fatal("expected cause to be reachable via errors.Is")
+			}
+			if defaultErr := tt.build("", nil); defaultErr.Error() != tt.marker.Msg {
+				t.Fatalf("expected default message %q, got %q", tt.marker.Msg, defaultErr.Error())
+			}
+		})
+	}
+	if errors.Is(NotFound("", nil), ErrConflictMarker) {
+		t.Fatal("expected different codes not to match")
+	}
+	if !IsRetryable(CacheFailure("", nil)) || IsRetryable(CacheMiss("", nil)) {
+		t.Fatal("expected cache failures to inherit the retryable flag of ErrRedisRequest")
+	}
+	if bizErr, _ := AsBizError(CacheMiss("", nil)); bizErr.Severity() != SeverityInfo {
+		t.Fatalf("expected cache miss severity info, got %v", bizErr.Severity())
+	}
+}
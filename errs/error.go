@@ -8,10 +8,15 @@ import (
 // ErrorCode 业务错误码类型
 type ErrorCode int32
 
-// BizError 业务错误
+// BizError 业务错误，Retryable 标记错误是否为可重试的临时故障，TextCode 为对外响应使用的文本错误码
 type BizError struct {
-	Code ErrorCode
-	Msg  string
+	Code      ErrorCode
+	TextCode  string
+	Msg       string
+	Retryable bool
+	severity  Severity
+	cause     error
+	stack     string
 }
 
 // Error 实现 error 接口
@@ -19,23 +24,57 @@ func (e *BizError) Error() string {
 	return e.Msg
 }
 
+// Unwrap 返回被包装的底层错误
+func (e *BizError) Unwrap() error {
+	return e.cause
+}
+
+// Is 按错误码匹配，使 errors.Is(err, ErrNotFoundMarker) 对错误链中任意同码 BizError 成立
+func (e *BizError) Is(target error) bool {
+	targetErr, ok := target.(*BizError)
+	return ok && targetErr.Code == e.Code
+}
+
+// CodeText 返回文本错误码，未设置 TextCode 时使用错误码注册的名称
+func (e *BizError) CodeText() string {
+	if e.TextCode != "" {
+		return e.TextCode
+	}
+	return e.Code.String()
+}
+
+// Stack 返回创建错误时捕获的调用栈，未开启栈捕获时为空
+func (e *BizError) Stack() string {
+	return e.stack
+}
+
 // ErrorReply proto 响应结构体可选实现的接口，用于高效写入错误码和消息
 type ErrorReply interface {
 	SetCode(int32)
 	SetMessage(string)
 }
 
+// TextCodeReply proto 响应结构体可选实现的接口，用于写入文本错误码
+type TextCodeReply interface {
+	SetTextCode(string)
+}
+
 // New 创建 BizError 并返回 error 接口
 func New(code ErrorCode, msg string) error {
 	return &BizError{Code: code, Msg: msg}
 }
 
+// NewRetryable 创建可重试的 BizError，用于 DB/Redis 等临时故障
+func NewRetryable(code ErrorCode, msg string) error {
+	return &BizError{Code: code, Msg: msg, Retryable: true}
+}
+
 // newBizError 创建 BizError 指针
 func newBizError(code ErrorCode, msg string) *BizError {
 	return &BizError{Code: code, Msg: msg}
 }
 
-// SetErrMsg 将 BizError 的 Code/Msg 写入 rsp，优先使用接口，其次反射
+// SetErrMsg 将 BizError 的 Code/Msg 写入 rsp，优先使用接口，其次反射；rsp 实现 TextCodeReply 时同时写入文本错误码
 func SetErrMsg(rsp interface{}, eFrom error) error {
 	bizErr, ok := eFrom.(*BizError)
 	if !ok {
@@ -44,6 +83,9 @@ func SetErrMsg(rsp interface{}, eFrom error) error {
 	if reply, ok := rsp.(ErrorReply); ok {
 		reply.SetCode(int32(bizErr.Code))
 		reply.SetMessage(bizErr.Msg)
+		if textReply, ok := rsp.(TextCodeReply); ok {
+			textReply.SetTextCode(bizErr.CodeText())
+		}
 		return nil
 	}
 	return setErrMsgByReflect(rsp, bizErr)
@@ -67,6 +109,10 @@ func setErrMsgByReflect(rsp interface{}, bizErr *BizError) error {
 	if msgField.IsValid() && msgField.CanSet() && msgField.Kind() == reflect.String {
 		msgField.SetString(bizErr.Msg)
 	}
+	textCodeField := elem.FieldByName("TextCode")
+	if textCodeField.IsValid() && textCodeField.CanSet() && textCodeField.Kind() == reflect.String {
+		textCodeField.SetString(bizErr.CodeText())
+	}
 	return nil
 }
 
@@ -0,0 +1,42 @@
+package errs
+
+import "sync"
+
+// DefaultLocale 未命中请求语言时回退使用的语言
+const DefaultLocale = "zh-CN"
+
+type messageKey struct {
+	code   ErrorCode
+	locale string
+}
+
+var (
+	messagesMu sync.RWMutex
+	messages   = make(map[messageKey]string)
+)
+
+// RegisterMessage 注册错误码在指定语言下的消息，重复注册时覆盖
+func RegisterMessage(code ErrorCode, locale, msg string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	messages[messageKey{code: code, locale: locale}] = msg
+}
+
+// LocalizedMsg 查找错误码在指定语言下的消息，未命中时回退到 DefaultLocale，均未注册时返回空字符串
+func LocalizedMsg(code ErrorCode, locale string) string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	if msg, ok := messages[messageKey{code: code, locale: locale}]; ok {
+		return msg
+	}
+	return messages[messageKey{code: code, locale: DefaultLocale}]
+}
+
+// Localize 返回消息翻译为指定语言的副本，目录中无对应消息时保留原 Msg
+func (e *BizError) Localize(locale string) *BizError {
+	clone := *e
+	if msg := LocalizedMsg(e.Code, locale); msg != "" {
+		clone.Msg = msg
+	}
+	return &clone
+}
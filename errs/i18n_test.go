@@ -0,0 +1,36 @@
+package errs
+
+import "testing"
+
+// TestLocalizedMsg 校验语言命中、回退默认语言与未知错误码
+func TestLocalizedMsg(t *testing.T) {
+	code := ErrorCode(40401)
+	RegisterMessage(code, DefaultLocale, "订单不存在")
+	RegisterMessage(code, "en-US", "order not found")
+
+	if msg := LocalizedMsg(code, "en-US"); msg != "order not found" {
+		t.Fatalf("expected english message, got %q", msg)
+	}
+	if msg := LocalizedMsg(code, "ja-JP"); msg != "订单不存在" {
+		t.Fatalf("expected default locale fallback, got %q", msg)
+	}
+	if msg := LocalizedMsg(ErrorCode(49999), "en-US"); msg != "" {
+		t.Fatalf("expected empty message for unknown code, got %q", msg)
+	}
+}
+
+// TestBizErrorLocalize 校验 Localize 返回翻译副本且未注册时保留原消息
+func TestBizErrorLocalize(t *testing.T) {
+	RegisterMessage(ERR_CODE_JSON_MARSHAL, "en-US", "json marshal failed")
+
+	localized := ErrJsonMarshal.Localize("en-US")
+	if localized.Msg != "json marshal failed" || localized.Code != ERR_CODE_JSON_MARSHAL {
+		t.Fatalf("unexpected localized error: %+v", localized)
+	}
+	if ErrJsonMarshal.Msg != "Json压缩失败" {
+		t.Fatalf("expected original message untouched, got %q", ErrJsonMarshal.Msg)
+	}
+	if msg := ErrJsonUnmarshal.Localize("en-US").Msg; msg != "Json解压失败" {
+		t.Fatalf("expected registered default message, got %q", msg)
+	}
+}
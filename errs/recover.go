@@ -0,0 +1,42 @@
+package errs
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+var stackCapture atomic.Bool
+
+// EnableStackCapture 开启或关闭 Recover 捕获 panic 时的调用栈记录
+func EnableStackCapture(enabled bool) {
+	stackCapture.Store(enabled)
+}
+
+// Recover 返回用于 defer 的 panic 恢复函数，将 panic 转换为指定错误码的 BizError 并写入命名返回值 err：
+//
+//	func handle() (err error) {
+//		defer errs.Recover(errs.ERR_CODE_INTERNAL)(&err)
+//		...
+//	}
+//
+// 原始 panic 值作为 BizError 的底层错误可通过 errors.Unwrap 获取，开启 EnableStackCapture 时附带调用栈
+func Recover(code ErrorCode) func(*error) {
+	return func(errPtr *error) {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		cause, ok := recovered.(error)
+		if !ok {
+			cause = fmt.Errorf("panic: %v", recovered)
+		}
+		bizErr := &BizError{Code: code, Msg: ErrInternal.Msg, cause: cause}
+		if stackCapture.Load() {
+			bizErr.stack = string(debug.Stack())
+		}
+		if errPtr != nil {
+			*errPtr = bizErr
+		}
+	}
+}
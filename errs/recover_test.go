@@ -0,0 +1,61 @@
+package errs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// panicking 模拟在使用 Recover 的函数内部发生 panic
+func panicking(value any) (err error) {
+	defer Recover(ERR_CODE_INTERNAL)(&err)
+	panic(value)
+}
+
+// TestRecoverPanic 校验 panic 被转换为 BizError 并保留原始错误与调用栈
+func TestRecoverPanic(t *testing.T) {
+	EnableStackCapture(true)
+	defer EnableStackCapture(false)
+
+	rootErr := errors.New("nil map write")
+	err := panicking(rootErr)
+	bizErr, ok := AsBizError(err)
+	if !ok {
+		t.Fatalf("expected BizError, got %v", err)
+	}
+	if bizErr.Code != ERR_CODE_INTERNAL || bizErr.Msg != ErrInternal.Msg {
+		t.Fatalf("unexpected biz error: code=%d msg=%s", bizErr.Code, bizErr.Msg)
+	}
+	if !errors.Is(err, rootErr) {
+		t.Fatal("expected panic error to be wrapped")
+	}
+	if !strings.Contains(bizErr.Stack(), "panicking") {
+		t.Fatalf("expected stack to contain panicking frame, got %q", bizErr.Stack())
+	}
+}
+
+// TestRecoverNonErrorPanic 校验非 error 类型的 panic 值与未开启栈捕获的情况
+func TestRecoverNonErrorPanic(t *testing.T) {
+	err := panicking("boom")
+	bizErr, ok := AsBizError(err)
+	if !ok {
+		t.Fatalf("expected BizError, got %v", err)
+	}
+	if cause := errors.Unwrap(bizErr); cause == nil || cause.Error() != "panic: boom" {
+		t.Fatalf("unexpected cause: %v", cause)
+	}
+	if bizErr.Stack() != "" {
+		t.Fatal("expected empty stack when capture is disabled")
+	}
+}
+
+// TestRecoverNoPanic 校验未发生 panic 时不修改返回值
+func TestRecoverNoPanic(t *testing.T) {
+	fn := func() (err error) {
+		defer Recover(ERR_CODE_INTERNAL)(&err)
+		return nil
+	}
+	if err := fn(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
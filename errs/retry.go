@@ -0,0 +1,40 @@
+package errs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const defaultRetryInterval = 50 * time.Millisecond
+
+// IsRetryable 沿错误链查找 BizError 并返回其是否可重试，链中不含 BizError 时视为不可重试
+func IsRetryable(err error) bool {
+	bizErr, ok := AsBizError(err)
+	return ok && bizErr.Retryable
+}
+
+// Retry 最多执行 fn maxAttempts 次，仅在返回可重试错误时按线性递增间隔重试，
+// 遇到不可重试错误立即返回，重试耗尽时返回最后一次的错误
+func Retry(ctx context.Context, maxAttempts int, fn func() error) error {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = fn(); err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt == maxAttempts {
+			break
+		}
+		timer := time.NewTimer(time.Duration(attempt) * defaultRetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry aborted after %d attempts: %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestIsRetryable 校验可重试标记沿错误链识别
+func TestIsRetryable(t *testing.T) {
+	if !IsRetryable(fmt.Errorf("wrap: %w", ErrRedisRequest)) {
+		t.Fatal("expected wrapped redis error to be retryable")
+	}
+	if IsRetryable(New(ErrorCode(400), "bad request")) {
+		t.Fatal("expected plain BizError to be non-retryable")
+	}
+	if IsRetryable(errors.New("plain")) {
+		t.Fatal("expected non-BizError to be non-retryable")
+	}
+}
+
+// TestRetryEventuallySucceeds 校验可重试错误重试后成功
+func TestRetryEventuallySucceeds(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), 5, func() error {
+		attempts++
+		if attempts < 3 {
+			return NewRetryable(ERR_CODE_REDIS_REQUEST, "redis timeout")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+// TestRetryNonRetryableFailsFast 校验不可重试错误立即返回
+func TestRetryNonRetryableFailsFast(t *testing.T) {
+	attempts := 0
+	bizErr := New(ErrorCode(404), "not found")
+	err := Retry(context.Background(), 5, func() error {
+		attempts++
+		return bizErr
+	})
+	if !errors.Is(err, bizErr) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+// TestRetryExhausted 校验重试耗尽后返回最后一次错误
+func TestRetryExhausted(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), 2, func() error {
+		attempts++
+		return ErrRedisRequest
+	})
+	if !errors.Is(err, ErrRedisRequest) || attempts != 2 {
+		t.Fatalf("expected redis error after 2 attempts, got %v after %d", err, attempts)
+	}
+}
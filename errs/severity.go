@@ -0,0 +1,54 @@
+package errs
+
+// Severity 错误严重程度，独立于错误码用于告警分级
+type Severity int8
+
+const (
+	// SeverityUnspecified 未显式指定，按错误码类别推断
+	SeverityUnspecified Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityCritical
+)
+
+// String 返回严重程度名称
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unspecified"
+	}
+}
+
+// Severity 返回错误严重程度，未显式指定时按错误码推断：
+// 系统级错误码 100~199 与 500~599 为 critical，400~499 客户端错误为 info，其余业务错误为 warning
+func (e *BizError) Severity() Severity {
+	if e.severity != SeverityUnspecified {
+		return e.severity
+	}
+	return inferSeverity(e.Code)
+}
+
+// WithSeverity 返回指定严重程度的副本，不修改原错误，预定义错误实例可安全调用
+func (e *BizError) WithSeverity(severity Severity) *BizError {
+	clone := *e
+	clone.severity = severity
+	return &clone
+}
+
+// inferSeverity 按错误码类别推断严重程度
+func inferSeverity(code ErrorCode) Severity {
+	switch {
+	case code >= 100 && code < 200, code >= 500 && code < 600:
+		return SeverityCritical
+	case code >= 400 && code < 500:
+		return SeverityInfo
+	default:
+		return SeverityWarning
+	}
+}
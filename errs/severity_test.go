@@ -0,0 +1,41 @@
+package errs
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestSeverityInferred 校验未指定时按错误码类别推断严重程度
+func TestSeverityInferred(t *testing.T) {
+	cases := []struct {
+		code     ErrorCode
+		expected Severity
+	}{
+		{ERR_CODE_REDIS_REQUEST, SeverityCritical},
+		{ErrorCode(404), SeverityInfo},
+		{ErrorCode(500), SeverityCritical},
+		{ErrorCode(20001), SeverityWarning},
+		{ErrorCode(300), SeverityWarning},
+	}
+	for _, c := range cases {
+		bizErr, _ := AsBizError(New(c.code, "msg"))
+		if got := bizErr.Severity(); got != c.expected {
+			t.Fatalf("code %d: expected %s, got %s", c.code, c.expected, got)
+		}
+	}
+}
+
+// TestWithSeverityOverride 校验 WithSeverity 覆盖推断结果且不修改原错误
+func TestWithSeverityOverride(t *testing.T) {
+	overridden := ErrJsonMarshal.WithSeverity(SeverityWarning)
+	if overridden.Severity() != SeverityWarning {
+		t.Fatalf("expected warning, got %s", overridden.Severity())
+	}
+	if ErrJsonMarshal.Severity() != SeverityCritical {
+		t.Fatalf("expected original to stay critical, got %s", ErrJsonMarshal.Severity())
+	}
+	bizErr, ok := AsBizError(fmt.Errorf("wrap: %w", overridden))
+	if !ok || bizErr.Severity() != SeverityWarning || bizErr.Code != ERR_CODE_JSON_MARSHAL {
+		t.Fatalf("unexpected wrapped severity: %+v", bizErr)
+	}
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultCheckTimeout ctx 未设置截止时间时 Check 使用的整体超时
+const defaultCheckTimeout = 3 * time.Second
+
+// Status 健康状态
+type Status string
+
+const (
+	// StatusUp 全部组件正常
+	StatusUp Status = "up"
+	// StatusDegraded 部分组件异常
+	StatusDegraded Status = "degraded"
+	// StatusDown 全部组件异常
+	StatusDown Status = "down"
+)
+
+// CheckFunc 组件提供的检查函数，返回 nil 表示正常，应在 ctx 结束前返回
+// 如 redisClient.Ping、tracing.HealthCheck
+type CheckFunc func(ctx context.Context) error
+
+// ComponentResult 单个组件的检查结果
+type ComponentResult struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report 聚合检查结果，Components 按注册顺序排列
+type Report struct {
+	Status     Status            `json:"status"`
+	Components []ComponentResult `json:"components"`
+	Duration   time.Duration     `json:"duration_ns"`
+}
+
+// namedCheck 带名称的检查函数
+type namedCheck struct {
+	name string
+	fn   CheckFunc
+}
+
+// Health 健康检查聚合器，各模块通过 Register 注册检查函数，Check 并发执行并汇总
+type Health struct {
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// New 创建空的健康检查聚合器
+func New() *Health {
+	return &Health{}
+}
+
+// Register 注册组件检查函数，nil 函数被忽略
+func (h *Health) Register(name string, fn CheckFunc) {
+	if fn == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, namedCheck{name: name, fn: fn})
+}
+
+// Check 并发执行全部检查并返回聚合结果：全部正常为 up，部分异常为 degraded，全部异常为 down，未注册任何检查时为 up
+// ctx 未设置截止时间时使用 defaultCheckTimeout；超时未返回的检查记为异常，不再等待其结束
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.Lock()
+	checks := append([]namedCheck(nil), h.checks...)
+	h.mu.Unlock()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultCheckTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	results := make([]ComponentResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Status != StatusUp {
+			failed++
+		}
+	}
+	status := StatusUp
+	switch {
+	case failed == 0:
+	case failed == len(results):
+		status = StatusDown
+	default:
+		status = StatusDegraded
+	}
+	return Report{Status: status, Components: results, Duration: time.Since(start)}
+}
+
+// runCheck 在独立协程中执行检查，ctx 结束时不再等待并记为异常
+func runCheck(ctx context.Context, check namedCheck) ComponentResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := ComponentResult{Name: check.name, Status: StatusUp, Duration: time.Since(start)}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler 返回输出 JSON 报告的 HTTP 处理器，可挂载到 /healthz；状态为 down 时返回 503，其余返回 200
+func (h *Health) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := h.Check(req.Context())
+		statusCode := http.StatusOK
+		if report.Status == StatusDown {
+			statusCode = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
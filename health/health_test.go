@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckDegraded(t *testing.T) {
+	h := New()
+	h.Register("redis", func(context.Context) error { return nil })
+	h.Register("tracing", func(context.Context) error { return errors.New("tracer provider not initialized") })
+
+	report := h.Check(context.Background())
+	if report.Status != StatusDegraded {
+		t.Fatalf("Status = %q, want %q", report.Status, StatusDegraded)
+	}
+	if len(report.Components) != 2 {
+		t.Fatalf("len(Components) = %d, want 2", len(report.Components))
+	}
+	if got := report.Components[0]; got.Name != "redis" || got.Status != StatusUp || got.Error != "" {
+		t.Fatalf("Components[0] = %+v, want healthy redis", got)
+	}
+	if got := report.Components[1]; got.Name != "tracing" || got.Status != StatusDown || got.Error == "" {
+		t.Fatalf("Components[1] = %+v, want failing tracing", got)
+	}
+}
+
+func TestHealthCheckAggregateStatus(t *testing.T) {
+	healthy := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("boom") }
+	tests := []struct {
+		name   string
+		checks []CheckFunc
+		want   Status
+	}{
+		{name: "no checks", want: StatusUp},
+		{name: "all healthy", checks: []CheckFunc{healthy, healthy}, want: StatusUp},
+		{name: "all failing", checks: []CheckFunc{failing, failing}, want: StatusDown},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			h := New()
+			for _, check := range testCase.checks {
+				h.Register("component", check)
+			}
+			if got := h.Check(context.Background()).Status; got != testCase.want {
+				t.Fatalf("Status = %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	h := New()
+	h.Register("slow", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	report := h.Check(ctx)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Check waited %v for a blocking check", elapsed)
+	}
+	if report.Status != StatusDown || report.Components[0].Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("report = %+v, want down with deadline exceeded", report)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	h := New()
+	h.Register("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	recorder := httptest.NewRecorder()
+	h.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status code = %d, want %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,100 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultShutdownTimeout ctx 未设置截止时间时 Shutdown 使用的整体超时
+const defaultShutdownTimeout = 10 * time.Second
+
+// CloseFunc 模块注册的关闭函数，应在 ctx 结束前返回
+type CloseFunc func(ctx context.Context) error
+
+// namedCloser 带名称的关闭函数，名称用于错误信息
+type namedCloser struct {
+	name string
+	fn   CloseFunc
+}
+
+// Registry 关闭函数注册表，Shutdown 按注册的逆序（LIFO）依次执行，
+// 先初始化的基础模块（如日志）最后关闭，保证其余模块关闭期间的日志和链路数据不丢失
+type Registry struct {
+	mu      sync.Mutex
+	closers []namedCloser
+}
+
+// NewRegistry 创建空的关闭函数注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register 注册关闭函数，nil 函数被忽略
+func (r *Registry) Register(name string, fn CloseFunc) {
+	if fn == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closers = append(r.closers, namedCloser{name: name, fn: fn})
+}
+
+// Shutdown 按 LIFO 顺序执行已注册的关闭函数并清空注册表，单个函数失败不影响后续函数，错误合并返回
+// ctx 未设置截止时间时使用 defaultShutdownTimeout；超时后不再等待未完成的函数，剩余函数跳过并返回 ctx 错误
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	closers := r.closers
+	r.closers = nil
+	r.mu.Unlock()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultShutdownTimeout)
+		defer cancel()
+	}
+
+	var errList []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		closer := closers[i]
+		if err := runCloser(ctx, closer.fn); err != nil {
+			errList = append(errList, fmt.Errorf("lifecycle: close %s: %w", closer.name, err))
+		}
+		if ctx.Err() != nil {
+			if i > 0 {
+				errList = append(errList, fmt.Errorf("lifecycle: %d closers skipped: %w", i, ctx.Err()))
+			}
+			break
+		}
+	}
+	return errors.Join(errList...)
+}
+
+// runCloser 在独立协程中执行关闭函数，ctx 结束时不再等待其返回
+func runCloser(ctx context.Context, fn CloseFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// defaultRegistry 进程级默认注册表
+var defaultRegistry = NewRegistry()
+
+// Register 向默认注册表注册关闭函数
+func Register(name string, fn CloseFunc) {
+	defaultRegistry.Register(name, fn)
+}
+
+// Shutdown 按 LIFO 顺序执行默认注册表中的关闭函数
+func Shutdown(ctx context.Context) error {
+	return defaultRegistry.Shutdown(ctx)
+}
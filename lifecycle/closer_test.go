@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistryShutdownLIFO(t *testing.T) {
+	registry := NewRegistry()
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	for _, name := range []string{"logger", "tracing", "redis"} {
+		registry.Register(name, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := registry.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	want := []string{"redis", "tracing", "logger"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("close order = %v, want %v", order, want)
+	}
+
+	if err := registry.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown() error = %v", err)
+	}
+	if len(order) != 3 {
+		t.Fatalf("closers should run only once, got %v", order)
+	}
+}
+
+func TestRegistryShutdownDeadline(t *testing.T) {
+	registry := NewRegistry()
+	closeErr := errors.New("flush failed")
+	var loggerClosed bool
+	registry.Register("logger", func(context.Context) error {
+		loggerClosed = true
+		return nil
+	})
+	registry.Register("tracing", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	})
+	registry.Register("redis", func(context.Context) error {
+		return closeErr
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := registry.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Shutdown() should return at the deadline, took %v", elapsed)
+	}
+	if !errors.Is(err, closeErr) {
+		t.Fatalf("Shutdown() error = %v, want wrapped %v", err, closeErr)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want deadline exceeded", err)
+	}
+	if loggerClosed {
+		t.Fatal("closers after the deadline should be skipped")
+	}
+}
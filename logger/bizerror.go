@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereal3x/apc/errs"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// BizErrorLevelPolicy 根据业务错误码决定日志级别
+type BizErrorLevelPolicy func(code errs.ErrorCode) zapcore.Level
+
+// bizErrorLevelPolicy 当前生效的级别策略，未设置时使用 DefaultBizErrorLevel
+var bizErrorLevelPolicy atomic.Pointer[BizErrorLevelPolicy]
+
+// DefaultBizErrorLevel 默认级别策略，4xx 客户端错误记为 warn，其余错误码记为 error
+func DefaultBizErrorLevel(code errs.ErrorCode) zapcore.Level {
+	if code >= 400 && code < 500 {
+		return zapcore.WarnLevel
+	}
+	return zapcore.ErrorLevel
+}
+
+// SetBizErrorLevelPolicy 设置业务错误的日志级别策略，传入 nil 时恢复默认策略
+func SetBizErrorLevelPolicy(policy BizErrorLevelPolicy) {
+	if policy == nil {
+		bizErrorLevelPolicy.Store(nil)
+		return
+	}
+	bizErrorLevelPolicy.Store(&policy)
+}
+
+// currentBizErrorLevelPolicy 返回当前生效的级别策略
+func currentBizErrorLevelPolicy() BizErrorLevelPolicy {
+	if policy := bizErrorLevelPolicy.Load(); policy != nil {
+		return *policy
+	}
+	return DefaultBizErrorLevel
+}
+
+// LogBizError 按错误码策略决定级别记录错误，错误链中不含 BizError 时按 error 级别记录
+func LogBizError(ctx context.Context, err error, fields ...zap.Field) {
+	if err == nil {
+		return
+	}
+	level := zapcore.ErrorLevel
+	if bizErr, ok := errs.AsBizError(err); ok {
+		level = currentBizErrorLevelPolicy()(bizErr.Code)
+		fields = append(fields, zap.Int32("code", int32(bizErr.Code)))
+	}
+	fields = append(fields, zap.Error(err))
+	currentLogger := withCallerSkip(L(), 1)
+	switch {
+	case level <= zapcore.DebugLevel:
+		currentLogger.ContextDebug(ctx, "biz error", fields...)
+	case level == zapcore.InfoLevel:
+		currentLogger.ContextInfo(ctx, "biz error", fields...)
+	case level == zapcore.WarnLevel:
+		currentLogger.ContextWarn(ctx, "biz error", fields...)
+	default:
+		currentLogger.ContextError(ctx, "biz error", fields...)
+	}
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereal3x/apc/errs"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLogBizErrorLevelPolicy 验证 not found 错误码记为 warn，internal 错误码记为 error，自定义策略生效
+func TestLogBizErrorLevelPolicy(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "bizerror.log")
+	SetLogger(NewLogger(&Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath}))
+	defer SetBizErrorLevelPolicy(nil)
+
+	ctx := context.Background()
+	LogBizError(ctx, errs.New(errs.ErrorCode(404), "not found"))
+	LogBizError(ctx, fmt.Errorf("query order: %w", errs.New(errs.ErrorCode(500), "internal")))
+	SetBizErrorLevelPolicy(func(errs.ErrorCode) zapcore.Level { return zapcore.InfoLevel })
+	LogBizError(ctx, errs.New(errs.ErrorCode(500), "downgraded"))
+	require.NoError(t, Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 3)
+
+	expected := []struct {
+		level string
+		code  float64
+	}{{"WARN", 404}, {"ERROR", 500}, {"INFO", 500}}
+	for i, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Equal(t, expected[i].level, record["level"])
+		require.Equal(t, expected[i].code, record["code"])
+		require.Contains(t, record["caller"], "bizerror_test.go")
+	}
+}
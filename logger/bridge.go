@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereal3x/apc/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// spanEventBridge 是否在 ContextError 时向当前 span 追加日志事件
+var spanEventBridge atomic.Bool
+
+// tracingErrorLogger 将 tracing.RecordError 转发为携带 trace_id 的 error 日志
+type tracingErrorLogger struct {
+	logger Logger
+}
+
+// LogSpanError 实现 tracing.ErrorLogger，记录 span 错误对应的日志
+func (bridge tracingErrorLogger) LogSpanError(ctx context.Context, err error) {
+	// 直接写入底层 zap logger，避免 ContextError 再次向 span 追加重复事件
+	if zapLogger, ok := bridge.logger.(*ZapLogger); ok {
+		zapLogger.logger.WithOptions(zap.AddCallerSkip(1)).Error("span error recorded", append(extractCtxFields(ctx), zap.Error(err))...)
+		return
+	}
+	bridge.logger.ContextError(ctx, "span error recorded", zap.Error(err))
+}
+
+// BridgeTracing 关联 logger 与 tracing：tracing.RecordError 同时输出 error 日志，
+// ContextError 在 span 内同时记录 span 事件；传入 nil 关闭联动
+func BridgeTracing(bridgeLogger Logger) {
+	if bridgeLogger == nil {
+		tracing.SetErrorLogger(nil)
+		spanEventBridge.Store(false)
+		return
+	}
+	tracing.SetErrorLogger(tracingErrorLogger{logger: bridgeLogger})
+	spanEventBridge.Store(true)
+}
+
+// addSpanLogEvent 在联动开启且 span 正在记录时追加日志事件
+func addSpanLogEvent(ctx context.Context, level, msg string) {
+	if !spanEventBridge.Load() {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent("log", trace.WithAttributes(
+		attribute.String("log.severity", level),
+		attribute.String("log.message", msg),
+	))
+}
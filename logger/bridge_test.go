@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereal3x/apc/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+// useTestSpanRecorder 安装内存 span 记录器作为全局 provider，测试结束后恢复
+func useTestSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+// TestBridgeTracingRecordError 验证一次 RecordError 同时产生 span 错误状态和携带 trace_id 的日志
+func TestBridgeTracingRecordError(t *testing.T) {
+	recorder := useTestSpanRecorder(t)
+	logPath := filepath.Join(t.TempDir(), "bridge.log")
+	bridgeLogger := NewLogger(&Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath})
+	BridgeTracing(bridgeLogger)
+	defer BridgeTracing(nil)
+
+	ctx, span := tracing.Start(context.Background(), "bridge-record-error")
+	tracing.RecordError(ctx, errors.New("bridge failure"))
+	span.End()
+	require.NoError(t, bridgeLogger.Sync())
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, codes.Error, ended[0].Status().Code)
+	assertLogContains(t, logPath, tracing.TraceID(ctx))
+	assertLogContains(t, logPath, "bridge failure")
+	assertLogContains(t, logPath, "bridge_test.go")
+}
+
+// TestBridgeTracingContextError 验证 ContextError 在 span 内追加日志事件
+func TestBridgeTracingContextError(t *testing.T) {
+	recorder := useTestSpanRecorder(t)
+	logPath := filepath.Join(t.TempDir(), "bridge-event.log")
+	bridgeLogger := NewLogger(&Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath})
+	BridgeTracing(bridgeLogger)
+	defer BridgeTracing(nil)
+
+	ctx, span := tracing.Start(context.Background(), "bridge-context-error")
+	bridgeLogger.ContextError(ctx, "handler failed", zap.String("k", "v"))
+	span.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	events := ended[0].Events()
+	require.Len(t, events, 1)
+	require.Equal(t, "log", events[0].Name)
+}
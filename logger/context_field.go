@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// contextField 已注册的上下文字段，ctxKey 为 context.Value 的键，logKey 为日志字段名
+type contextField struct {
+	ctxKey interface{}
+	logKey string
+}
+
+var (
+	contextFieldsMu sync.RWMutex
+	contextFields   []contextField
+)
+
+// RegisterContextField 注册需要从上下文提取到日志的字段（如 request_id、user_id、tenant_id），
+// Context* 系列方法会将 ctx.Value(ctxKey) 以 logKey 为字段名写入日志；同一 ctxKey 重复注册时覆盖字段名。
+// string 与 fmt.Stringer 按文本输出，整数、布尔等基础类型保留原类型，其余类型交由 zap.Any 序列化
+func RegisterContextField(ctxKey interface{}, logKey string) {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+	for i := range contextFields {
+		if contextFields[i].ctxKey == ctxKey {
+			contextFields[i].logKey = logKey
+			return
+		}
+	}
+	contextFields = append(contextFields, contextField{ctxKey: ctxKey, logKey: logKey})
+}
+
+// appendRegisteredCtxFields 追加上下文中已注册字段的值，值为 nil 时跳过
+func appendRegisteredCtxFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	contextFieldsMu.RLock()
+	defer contextFieldsMu.RUnlock()
+	for _, field := range contextFields {
+		value := ctx.Value(field.ctxKey)
+		if value == nil {
+			continue
+		}
+		fields = append(fields, contextFieldValue(field.logKey, value))
+	}
+	return fields
+}
+
+// contextFieldValue 将上下文值转换为日志字段
+func contextFieldValue(logKey string, value interface{}) zap.Field {
+	switch typed := value.(type) {
+	case string:
+		return zap.String(logKey, typed)
+	case fmt.Stringer:
+		return zap.Stringer(logKey, typed)
+	default:
+		return zap.Any(logKey, typed)
+	}
+}
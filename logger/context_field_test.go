@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type testCtxKey string
+
+// TestRegisterContextField 验证注册的上下文字段写入 ContextInfo 日志，非字符串值保留原类型，未注册或缺失的值不输出
+func TestRegisterContextField(t *testing.T) {
+	contextFieldsMu.Lock()
+	previousFields := contextFields
+	contextFields = nil
+	contextFieldsMu.Unlock()
+	t.Cleanup(func() {
+		contextFieldsMu.Lock()
+		contextFields = previousFields
+		contextFieldsMu.Unlock()
+	})
+	activeMu.RLock()
+	previousLogger := activeLogger
+	activeMu.RUnlock()
+	defer SetLogger(previousLogger)
+
+	core, observed := observer.New(zapcore.InfoLevel)
+	zapLogger, err := New(Config{}, core)
+	require.NoError(t, err)
+	SetLogger(zapLogger)
+
+	RegisterContextField(testCtxKey("request_id"), "request_id")
+	RegisterContextField(testCtxKey("user_id"), "user_id")
+	RegisterContextField(testCtxKey("tenant_id"), "tenant_id")
+
+	ctx := context.WithValue(context.Background(), testCtxKey("request_id"), "req-123")
+	ctx = context.WithValue(ctx, testCtxKey("user_id"), int64(42))
+	ContextInfo(ctx, "handled")
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	require.Equal(t, "req-123", fields["request_id"])
+	require.Equal(t, int64(42), fields["user_id"])
+	require.NotContains(t, fields, "tenant_id")
+}
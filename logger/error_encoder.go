@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"github.com/ethereal3x/apc/errs"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// compactErrorCore 改写 zap.Error 字段的 core 包装，仅保留错误消息并为 BizError 补充 error_code
+type compactErrorCore struct {
+	zapcore.Core
+}
+
+// wrapErrorCore 按配置为 core 包装紧凑错误编码，需包装在单个 ioCore 上以保留其级别判断
+func wrapErrorCore(core zapcore.Core, cfg Config) zapcore.Core {
+	if !cfg.CompactErrors {
+		return core
+	}
+	return compactErrorCore{Core: core}
+}
+
+// With 改写附加字段中的错误字段
+func (core compactErrorCore) With(fields []zapcore.Field) zapcore.Core {
+	return compactErrorCore{Core: core.Core.With(compactErrorFields(fields))}
+}
+
+// Check 级别启用时将自身加入 CheckedEntry，保证 Write 经过字段改写
+func (core compactErrorCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core.Enabled(entry.Level) {
+		return checked.AddCore(entry, core)
+	}
+	return checked
+}
+
+// Write 改写错误字段后写入
+func (core compactErrorCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return core.Core.Write(entry, compactErrorFields(fields))
+}
+
+// compactErrorFields 将错误字段替换为错误消息字符串，错误链中含 BizError 时追加 error_code 字段
+func compactErrorFields(fields []zapcore.Field) []zapcore.Field {
+	var compacted []zapcore.Field
+	for i, field := range fields {
+		err, ok := field.Interface.(error)
+		if field.Type != zapcore.ErrorType || !ok {
+			if compacted != nil {
+				compacted = append(compacted, field)
+			}
+			continue
+		}
+		if compacted == nil {
+			compacted = append(make([]zapcore.Field, 0, len(fields)+1), fields[:i]...)
+		}
+		compacted = append(compacted, zap.String(field.Key, err.Error()))
+		if bizErr, ok := errs.AsBizError(err); ok {
+			compacted = append(compacted, zap.Int32("error_code", int32(bizErr.Code)))
+		}
+	}
+	if compacted == nil {
+		return fields
+	}
+	return compacted
+}
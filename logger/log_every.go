@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// logEveryState 记录某个 key 最近一次输出的时间与之后被抑制的条数
+type logEveryState struct {
+	last       time.Time
+	suppressed int
+}
+
+var (
+	logEveryMu     sync.Mutex
+	logEveryStates = make(map[string]*logEveryState)
+)
+
+// LogEvery 按 key 限制日志输出频率，同一 key 在 interval 内最多输出一条，用于依赖故障等高频错误路径避免日志风暴；
+// 区别于 zap 采样，限流按调用方指定的 key 进行，输出时附带 suppressed 字段表示上次输出后被丢弃的条数。
+// key 应取自有限集合（如 "redis down"），不要包含请求 ID 等高基数值，否则限流状态会持续增长
+func LogEvery(ctx context.Context, key string, interval time.Duration, level LevelConfig, msg string, fields ...zap.Field) {
+	suppressed, ok := allowLogEvery(key, interval, time.Now())
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		fields = append(fields, zap.Int("suppressed", suppressed))
+	}
+	currentLogger := withCallerSkip(L(), 1)
+	switch level {
+	case LevelDebug:
+		currentLogger.ContextDebug(ctx, msg, fields...)
+	case LevelWarn:
+		currentLogger.ContextWarn(ctx, msg, fields...)
+	case LevelError:
+		currentLogger.ContextError(ctx, msg, fields...)
+	case LevelPanic:
+		currentLogger.ContextPanic(ctx, msg, fields...)
+	case LevelFatal:
+		currentLogger.Fatal(msg, append(extractCtxFields(ctx), fields...)...)
+	default:
+		currentLogger.ContextInfo(ctx, msg, fields...)
+	}
+}
+
+// allowLogEvery 判断 key 在 now 时刻是否允许输出，允许时返回上次输出后被抑制的条数并重置计数
+func allowLogEvery(key string, interval time.Duration, now time.Time) (int, bool) {
+	logEveryMu.Lock()
+	defer logEveryMu.Unlock()
+	state, ok := logEveryStates[key]
+	if !ok {
+		logEveryStates[key] = &logEveryState{last: now}
+		return 0, true
+	}
+	if now.Sub(state.last) < interval {
+		state.suppressed++
+		return 0, false
+	}
+	suppressed := state.suppressed
+	state.last = now
+	state.suppressed = 0
+	return suppressed, true
+}
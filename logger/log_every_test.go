@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestLogEvery 验证同一 key 在间隔内只输出一条，不同 key 互不影响
+func TestLogEvery(t *testing.T) {
+	activeMu.RLock()
+	previous := activeLogger
+	activeMu.RUnlock()
+	defer SetLogger(previous)
+
+	core, observed := observer.New(zapcore.DebugLevel)
+	zapLogger, err := New(Config{}, core)
+	require.NoError(t, err)
+	SetLogger(zapLogger)
+
+	ctx := WithTraceID(context.Background(), "trace-log-every")
+	for i := 0; i < 100; i++ {
+		LogEvery(ctx, "test_log_every:redis down", time.Second, LevelError, "redis down")
+	}
+	LogEvery(ctx, "test_log_every:db down", time.Second, LevelWarn, "db down")
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 2)
+	require.Equal(t, "redis down", entries[0].Message)
+	require.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	require.Equal(t, "trace-log-every", entries[0].ContextMap()["trace_id"])
+	require.Contains(t, entries[0].Caller.File, "log_every_test.go")
+	require.Equal(t, "db down", entries[1].Message)
+}
+
+// TestAllowLogEverySuppressedCount 验证间隔过后再次放行，并返回期间被抑制的条数
+func TestAllowLogEverySuppressedCount(t *testing.T) {
+	start := time.Now()
+	key := "test_allow_log_every"
+	_, ok := allowLogEvery(key, time.Second, start)
+	require.True(t, ok)
+	for i := 0; i < 3; i++ {
+		_, ok = allowLogEvery(key, time.Second, start.Add(500*time.Millisecond))
+		require.False(t, ok)
+	}
+	suppressed, ok := allowLogEvery(key, time.Second, start.Add(time.Second))
+	require.True(t, ok)
+	require.Equal(t, 3, suppressed)
+}
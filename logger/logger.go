@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/ethereal3x/apc/tracing"
 	"go.uber.org/zap"
@@ -51,19 +53,63 @@ type Logger interface {
 // ZapLogger 基于 zap 实现 Logger 接口
 type ZapLogger struct {
 	logger *zap.Logger
+	// closers 释放缓冲写入协程与日志文件句柄，Close 时按逆序执行
+	closers []func() error
 }
 
 type callerSkipLogger interface {
 	withCallerSkip(skip int) Logger
 }
 
-var activeLogger Logger
+var (
+	activeMu     sync.RWMutex
+	activeLogger Logger
+)
 
-// Config 日志配置
+// Config 日志配置，DisableColor 关闭 console 级别颜色，DisableCaller 不输出调用位置
+// TimeFormat 支持 iso8601(默认) / rfc3339 / rfc3339nano / epoch / epoch_millis 或任意 Go 时间布局
 type Config struct {
-	Level      LevelConfig  `mapstructure:"level" json:"level" yaml:"level"`
-	Format     FormatConfig `mapstructure:"format" json:"format" yaml:"format"`
-	OutputPath string       `mapstructure:"output_path" json:"logfile" yaml:"logfile"`
+	Level         LevelConfig  `mapstructure:"level" json:"level" yaml:"level"`
+	Format        FormatConfig `mapstructure:"format" json:"format" yaml:"format"`
+	OutputPath    string       `mapstructure:"output_path" json:"logfile" yaml:"logfile"`
+	DisableColor  bool         `mapstructure:"disable_color" json:"disable_color" yaml:"disable_color"`
+	DisableCaller bool         `mapstructure:"disable_caller" json:"disable_caller" yaml:"disable_caller"`
+	TimeFormat    string       `mapstructure:"time_format" json:"time_format" yaml:"time_format"`
+	KeyNames      KeyNames     `mapstructure:"key_names" json:"key_names" yaml:"key_names"`
+	Buffer        BufferConfig `mapstructure:"buffer" json:"buffer" yaml:"buffer"`
+	// DisableStdoutWhenFile 设置 OutputPath 时仅写入文件，不再同时写入 stdout，避免采集文件的容器环境日志重复
+	DisableStdoutWhenFile bool `mapstructure:"disable_stdout_when_file" json:"disable_stdout_when_file" yaml:"disable_stdout_when_file"`
+	// DisableStacktrace 关闭 error 及以上级别日志自动附带的调用栈
+	DisableStacktrace bool `mapstructure:"disable_stacktrace" json:"disable_stacktrace" yaml:"disable_stacktrace"`
+	// CompactErrors 开启后 zap.Error 字段仅输出错误消息（不含 errorVerbose），BizError 额外输出数值型 error_code 字段
+	CompactErrors bool `mapstructure:"compact_errors" json:"compact_errors" yaml:"compact_errors"`
+	// Outputs 按级别区间拆分输出目标，设置后忽略 OutputPath；为空时所有级别共用 OutputPath
+	Outputs []OutputConfig `mapstructure:"outputs" json:"outputs" yaml:"outputs"`
+}
+
+// OutputConfig 级别区间输出配置，MinLevel/MaxLevel 留空表示不限制下界/上界，下界不低于 Config.Level
+// OutputPath 为空时写入 stdout，否则仅写入该文件
+type OutputConfig struct {
+	MinLevel   LevelConfig `mapstructure:"min_level" json:"min_level" yaml:"min_level"`
+	MaxLevel   LevelConfig `mapstructure:"max_level" json:"max_level" yaml:"max_level"`
+	OutputPath string      `mapstructure:"output_path" json:"logfile" yaml:"logfile"`
+}
+
+// BufferConfig 缓冲写入配置，开启后日志先写入内存缓冲，按大小或时间间隔批量刷盘，Sync 时强制刷新
+type BufferConfig struct {
+	Enabled       bool          `mapstructure:"enabled" json:"enabled" yaml:"enabled"`
+	Size          int           `mapstructure:"size" json:"size" yaml:"size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval" json:"flush_interval" yaml:"flush_interval"`
+}
+
+// KeyNames 覆盖编码器输出的字段名，留空保持默认
+type KeyNames struct {
+	Time       string `mapstructure:"time" json:"time" yaml:"time"`
+	Level      string `mapstructure:"level" json:"level" yaml:"level"`
+	Name       string `mapstructure:"name" json:"name" yaml:"name"`
+	Caller     string `mapstructure:"caller" json:"caller" yaml:"caller"`
+	Message    string `mapstructure:"message" json:"message" yaml:"message"`
+	Stacktrace string `mapstructure:"stacktrace" json:"stacktrace" yaml:"stacktrace"`
 }
 
 // NewLogger 创建日志实例
@@ -101,30 +147,159 @@ func fillDefaultConfig(cfg Config) Config {
 
 // NewZapLogger 根据配置创建 zap 日志实例
 func NewZapLogger(cfg Config) (*ZapLogger, error) {
+	core, closers, err := buildCore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ZapLogger{logger: zap.New(core, buildOptions(cfg)...), closers: closers}, nil
+}
+
+// New 使用调用方提供的 core（如内存缓冲、网络 sink 或 zaptest/observer）创建日志实例，
+// 传入 core 时忽略配置中的级别、格式与输出目标，仅沿用 DisableCaller 等选项；未传入时等同于 NewZapLogger
+func New(cfg Config, cores ...zapcore.Core) (*ZapLogger, error) {
+	if len(cores) == 0 {
+		return NewZapLogger(cfg)
+	}
+	return &ZapLogger{logger: zap.New(zapcore.NewTee(cores...), buildOptions(cfg)...)}, nil
+}
+
+// buildOptions 根据配置创建 zap 选项
+func buildOptions(cfg Config) []zap.Option {
+	// 预留一层 caller skip 抵消 ZapLogger 方法自身的栈帧，包级函数（含 Context* 系列）再通过 withCallerSkip 各加一层
+	options := []zap.Option{zap.AddCallerSkip(1)}
+	if !cfg.DisableCaller {
+		options = append(options, zap.AddCaller())
+	}
+	if !cfg.DisableStacktrace {
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+	return options
+}
+
+// buildCore 根据配置创建日志 core，配置了 Outputs 时为每个级别区间创建独立 core 并合并，
+// 同时返回释放文件句柄与缓冲写入协程的关闭函数
+func buildCore(cfg Config) (zapcore.Core, []func() error, error) {
 	// 解析日志级别
 	level := parseLevel(cfg.Level)
-	// 构建日志编码器
-	encoder := buildEncoder(cfg)
-	// 构建日志输出目标
-	writeSyncer, err := buildWriteSyncer(cfg.OutputPath)
-	if err != nil {
-		return nil, fmt.Errorf("build write syncer: %w", err)
+	var closers []func() error
+	if len(cfg.Outputs) == 0 {
+		// 构建日志输出目标，默认文件与 stdout 双写
+		build := buildWriteSyncer
+		if cfg.DisableStdoutWhenFile {
+			build = buildOutputWriteSyncer
+		}
+		writeSyncer, closeFile, err := build(cfg.OutputPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("build write syncer: %w", err)
+		}
+		writeSyncer, closers = wrapBufferedWriteSyncer(writeSyncer, cfg.Buffer), appendCloser(closers, closeFile)
+		closers = appendBufferCloser(closers, writeSyncer)
+		return wrapErrorCore(zapcore.NewCore(buildEncoder(cfg), writeSyncer, level), cfg), closers, nil
+	}
+	cores := make([]zapcore.Core, 0, len(cfg.Outputs))
+	for _, output := range cfg.Outputs {
+		writeSyncer, closeFile, err := buildOutputWriteSyncer(output.OutputPath)
+		if err != nil {
+			closeAll(closers)
+			return nil, nil, fmt.Errorf("build write syncer: %w", err)
+		}
+		writeSyncer, closers = wrapBufferedWriteSyncer(writeSyncer, cfg.Buffer), appendCloser(closers, closeFile)
+		closers = appendBufferCloser(closers, writeSyncer)
+		// 编码器按输出目标决定是否着色，文件输出不带颜色码
+		outputCfg := cfg
+		outputCfg.OutputPath = output.OutputPath
+		core := zapcore.NewCore(buildEncoder(outputCfg), writeSyncer, levelRange(level, output))
+		cores = append(cores, wrapErrorCore(core, cfg))
+	}
+	return zapcore.NewTee(cores...), closers, nil
+}
+
+// appendCloser 追加非空的关闭函数
+func appendCloser(closers []func() error, closer func() error) []func() error {
+	if closer == nil {
+		return closers
+	}
+	return append(closers, closer)
+}
+
+// appendBufferCloser 输出目标为缓冲写入时追加 Stop，关闭时先于文件执行以刷新剩余缓冲
+func appendBufferCloser(closers []func() error, writeSyncer zapcore.WriteSyncer) []func() error {
+	if buffered, ok := writeSyncer.(*zapcore.BufferedWriteSyncer); ok {
+		return append(closers, buffered.Stop)
+	}
+	return closers
+}
+
+// closeAll 按逆序执行关闭函数并合并错误
+func closeAll(closers []func() error) error {
+	var errList []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i](); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return errors.Join(errList...)
+}
+
+// levelRange 构建级别区间过滤器，下界取 MinLevel 与全局级别中较高者
+func levelRange(level zapcore.Level, output OutputConfig) zap.LevelEnablerFunc {
+	minLevel := level
+	if output.MinLevel != "" {
+		minLevel = max(minLevel, parseLevel(output.MinLevel))
+	}
+	maxLevel := zapcore.FatalLevel
+	if output.MaxLevel != "" {
+		maxLevel = parseLevel(output.MaxLevel)
+	}
+	return func(l zapcore.Level) bool {
+		return l >= minLevel && l <= maxLevel
 	}
-	core := zapcore.NewCore(encoder, writeSyncer, level)
-	return &ZapLogger{logger: zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))}, nil
 }
 
 // SetLogger 设置默认日志实例
 func SetLogger(newLogger Logger) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	activeLogger = newLogger
+}
+
+// reconfigureCloseDelay Reconfigure 替换后延迟关闭旧实例的时间，留给替换前通过 L() 取得旧实例的调用方完成写入
+var reconfigureCloseDelay = 5 * time.Second
+
+// Reconfigure 按新配置重建默认日志实例并原子替换，用于运行时切换格式、级别或输出目标；
+// 替换前刷新旧实例的缓冲内容，新配置构建失败时保留旧实例。
+// 旧实例持有的文件句柄与缓冲写入协程在 reconfigureCloseDelay 后关闭，期间替换前已取得的旧实例仍可正常写入；
+// 长期持有 L() 返回值的调用方（如缓存到结构体字段）应在替换后重新获取，否则延迟关闭后的写入会丢失
+func Reconfigure(cfg Config) error {
+	newLogger, err := NewZapLogger(fillDefaultConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("reconfigure logger: %w", err)
+	}
+	activeMu.Lock()
+	previous := activeLogger
+	if previous != nil {
+		_ = previous.Sync()
+	}
 	activeLogger = newLogger
+	activeMu.Unlock()
+
+	if closer, ok := previous.(interface{ Close() error }); ok {
+		time.AfterFunc(reconfigureCloseDelay, func() {
+			_ = closer.Close()
+		})
+	}
+	return nil
 }
 
 // Sync 同步默认日志实例的缓冲内容
 func Sync() error {
-	if activeLogger == nil {
+	activeMu.RLock()
+	currentLogger := activeLogger
+	activeMu.RUnlock()
+	if currentLogger == nil {
 		return nil
 	}
-	if err := activeLogger.Sync(); err != nil {
+	if err := currentLogger.Sync(); err != nil {
 		return fmt.Errorf("sync active logger: %w", err)
 	}
 	return nil
@@ -132,6 +307,8 @@ func Sync() error {
 
 // L 返回默认日志实例
 func L() Logger {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
 	if activeLogger == nil {
 		panic("logger not initialized")
 	}
@@ -203,16 +380,60 @@ func (zapLogger *ZapLogger) Sync() error {
 	return nil
 }
 
+// Close 刷新缓冲内容并释放日志文件句柄与缓冲写入协程，之后不应再使用该实例
+func (zapLogger *ZapLogger) Close() error {
+	syncErr := zapLogger.Sync()
+	closers := zapLogger.closers
+	zapLogger.closers = nil
+	return errors.Join(syncErr, closeAll(closers))
+}
+
 // withCallerSkip 返回调整调用栈层级后的 zap logger
 func (zapLogger *ZapLogger) withCallerSkip(skip int) Logger {
 	return &ZapLogger{logger: zapLogger.logger.WithOptions(zap.AddCallerSkip(skip))}
 }
 
+// Zap 返回可直接调用的底层 zap logger，caller 指向调用方代码
+func (zapLogger *ZapLogger) Zap() *zap.Logger {
+	// 内部 logger 为包装方法预留了一层 caller skip，直接使用时需抵消
+	return zapLogger.logger.WithOptions(zap.AddCallerSkip(-1))
+}
+
+// Named 返回带组件名称的子 logger
+func (zapLogger *ZapLogger) Named(name string) *zap.Logger {
+	return zapLogger.Zap().Named(name)
+}
+
+// WithFields 返回附带固定字段的子 logger
+func (zapLogger *ZapLogger) WithFields(fields ...zap.Field) *zap.Logger {
+	return zapLogger.Zap().With(fields...)
+}
+
+// Named 基于默认日志实例返回带组件名称的子 logger，默认实例非 ZapLogger 时返回 no-op logger
+func Named(name string) *zap.Logger {
+	return defaultZap().Named(name)
+}
+
+// WithFields 基于默认日志实例返回附带固定字段的子 logger，默认实例非 ZapLogger 时返回 no-op logger
+func WithFields(fields ...zap.Field) *zap.Logger {
+	return defaultZap().With(fields...)
+}
+
+// defaultZap 返回默认日志实例的底层 zap logger
+func defaultZap() *zap.Logger {
+	zapLogger, ok := L().(*ZapLogger)
+	if !ok {
+		return zap.NewNop()
+	}
+	return zapLogger.Zap()
+}
+
 type ctxKey string
 
 const (
 	ctxTraceID   ctxKey = "trace_id"
 	ctxSpanID    ctxKey = "span_id"
+	ctxLogger    ctxKey = "logger"
 	emptyTraceID        = "00000000000000000000000000000000"
 	emptySpanID         = "0000000000000000"
 )
@@ -227,6 +448,19 @@ func WithSpanID(ctx context.Context, spanID string) context.Context {
 	return context.WithValue(ctx, ctxSpanID, spanID)
 }
 
+// IntoContext 将已附加请求字段的 zap logger 写入上下文，供下游通过 FromContext 取用
+func IntoContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxLogger, l)
+}
+
+// FromContext 返回上下文中的 zap logger，未设置时返回默认日志实例的底层 zap logger
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(ctxLogger).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return defaultZap()
+}
+
 // ContextDebug 记录携带上下文字段的 debug 日志
 func ContextDebug(ctx context.Context, msg string, fields ...zap.Field) {
 	withCallerSkip(L(), 1).ContextDebug(ctx, msg, fields...)
@@ -265,6 +499,7 @@ func (zapLogger *ZapLogger) ContextWarn(ctx context.Context, msg string, fields
 // ContextError 记录 zap logger 携带上下文字段的 error 日志
 func (zapLogger *ZapLogger) ContextError(ctx context.Context, msg string, fields ...zap.Field) {
 	zapLogger.logger.Error(msg, append(extractCtxFields(ctx), fields...)...)
+	addSpanLogEvent(ctx, "error", msg)
 }
 
 func (zapLogger *ZapLogger) ContextPanic(ctx context.Context, msg string, fields ...zap.Field) {
@@ -292,15 +527,15 @@ func parseLevel(level LevelConfig) zapcore.Level {
 // buildEncoder 根据配置创建日志编码器
 func buildEncoder(cfg Config) zapcore.Encoder {
 	encoderCfg := zap.NewProductionEncoderConfig()
-	encoderCfg.TimeKey = "time"
-	encoderCfg.LevelKey = "level"
-	encoderCfg.NameKey = "logger"
-	encoderCfg.CallerKey = "caller"
-	encoderCfg.MessageKey = "msg"
-	encoderCfg.StacktraceKey = "stack"
-	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderCfg.TimeKey = keyOrDefault(cfg.KeyNames.Time, "time")
+	encoderCfg.LevelKey = keyOrDefault(cfg.KeyNames.Level, "level")
+	encoderCfg.NameKey = keyOrDefault(cfg.KeyNames.Name, "logger")
+	encoderCfg.CallerKey = keyOrDefault(cfg.KeyNames.Caller, "caller")
+	encoderCfg.MessageKey = keyOrDefault(cfg.KeyNames.Message, "msg")
+	encoderCfg.StacktraceKey = keyOrDefault(cfg.KeyNames.Stacktrace, "stack")
+	encoderCfg.EncodeTime = buildTimeEncoder(cfg.TimeFormat)
 	encoderCfg.EncodeCaller = zapcore.ShortCallerEncoder
-	if cfg.Format == FormatConsole && cfg.OutputPath == "" {
+	if cfg.Format == FormatConsole && cfg.OutputPath == "" && !cfg.DisableColor {
 		encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	} else {
 		encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
@@ -311,20 +546,70 @@ func buildEncoder(cfg Config) zapcore.Encoder {
 	return zapcore.NewConsoleEncoder(encoderCfg)
 }
 
-// buildWriteSyncer 根据输出路径创建日志输出目标
-func buildWriteSyncer(outputPath string) (zapcore.WriteSyncer, error) {
+// keyOrDefault 字段名为空时使用默认值
+func keyOrDefault(key, defaultKey string) string {
+	if key == "" {
+		return defaultKey
+	}
+	return key
+}
+
+// buildTimeEncoder 根据时间格式配置创建时间编码器，未识别的取值按 Go 时间布局处理
+func buildTimeEncoder(timeFormat string) zapcore.TimeEncoder {
+	switch timeFormat {
+	case "", "iso8601":
+		return zapcore.ISO8601TimeEncoder
+	case "rfc3339":
+		return zapcore.RFC3339TimeEncoder
+	case "rfc3339nano":
+		return zapcore.RFC3339NanoTimeEncoder
+	case "epoch":
+		return zapcore.EpochTimeEncoder
+	case "epoch_millis":
+		return zapcore.EpochMillisTimeEncoder
+	default:
+		return zapcore.TimeEncoderOfLayout(timeFormat)
+	}
+}
+
+// buildWriteSyncer 根据输出路径创建日志输出目标，写入文件时返回文件的关闭函数
+func buildWriteSyncer(outputPath string) (zapcore.WriteSyncer, func() error, error) {
 	writeSyncer := zapcore.AddSync(os.Stdout)
 	if outputPath == "" {
-		return writeSyncer, nil
+		return writeSyncer, nil, nil
+	}
+	file, err := os.OpenFile(outputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open log file %s: %w", outputPath, err)
+	}
+	return zapcore.NewMultiWriteSyncer(writeSyncer, zapcore.AddSync(file)), file.Close, nil
+}
+
+// buildOutputWriteSyncer 创建级别区间的输出目标，路径为空时写入 stdout，否则仅写入文件并返回文件的关闭函数
+func buildOutputWriteSyncer(outputPath string) (zapcore.WriteSyncer, func() error, error) {
+	if outputPath == "" {
+		return zapcore.AddSync(os.Stdout), nil, nil
 	}
 	file, err := os.OpenFile(outputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return nil, fmt.Errorf("open log file %s: %w", outputPath, err)
+		return nil, nil, fmt.Errorf("open log file %s: %w", outputPath, err)
+	}
+	return zapcore.AddSync(file), file.Close, nil
+}
+
+// wrapBufferedWriteSyncer 按配置为输出目标包装缓冲写入，未开启时原样返回，Size/FlushInterval 为 0 时使用 zap 默认值
+func wrapBufferedWriteSyncer(writeSyncer zapcore.WriteSyncer, cfg BufferConfig) zapcore.WriteSyncer {
+	if !cfg.Enabled {
+		return writeSyncer
+	}
+	return &zapcore.BufferedWriteSyncer{
+		WS:            writeSyncer,
+		Size:          cfg.Size,
+		FlushInterval: cfg.FlushInterval,
 	}
-	return zapcore.NewMultiWriteSyncer(writeSyncer, zapcore.AddSync(file)), nil
 }
 
-// extractCtxFields 提取上下文中的日志字段
+// extractCtxFields 提取上下文中的日志字段，包括链路 ID 与通过 RegisterContextField 注册的字段
 func extractCtxFields(ctx context.Context) []zap.Field {
 	fields := make([]zap.Field, 0, 2)
 	traceID := tracing.TraceID(ctx)
@@ -345,5 +630,5 @@ func extractCtxFields(ctx context.Context) []zap.Field {
 	if spanID != "" && spanID != emptySpanID {
 		fields = append(fields, zap.String("span_id", spanID))
 	}
-	return fields
+	return appendRegisteredCtxFields(ctx, fields)
 }
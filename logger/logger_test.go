@@ -2,15 +2,22 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereal3x/apc/errs"
 	"github.com/ethereal3x/apc/tracing"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestLoggerWithOTLPTrace 验证 logger 搭配 OTLP tracing 记录正常调用链
@@ -150,6 +157,330 @@ func assertTracingRecords(t *testing.T, records []tracingRecord) {
 	require.NotEqual(t, records[0].spanID, records[2].spanID)
 }
 
+// TestBuildEncoderDisableColor 验证 DisableColor 时 console 输出不含 ANSI 颜色码
+func TestBuildEncoderDisableColor(t *testing.T) {
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "color check"}
+
+	colored, err := buildEncoder(Config{Format: FormatConsole}).EncodeEntry(entry, nil)
+	require.NoError(t, err)
+	require.Contains(t, colored.String(), "\x1b[")
+
+	plain, err := buildEncoder(Config{Format: FormatConsole, DisableColor: true}).EncodeEntry(entry, nil)
+	require.NoError(t, err)
+	require.NotContains(t, plain.String(), "\x1b[")
+}
+
+// TestNewZapLoggerDisableCaller 验证 DisableCaller 时日志不含 caller 字段
+func TestNewZapLoggerDisableCaller(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "caller.log")
+	zapLogger, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath, DisableCaller: true})
+	require.NoError(t, err)
+	zapLogger.Info("caller check")
+	require.NoError(t, zapLogger.Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(logData), "caller check")
+	require.NotContains(t, string(logData), `"caller"`)
+}
+
+// TestBuildEncoderTimeFormatAndKeyNames 验证时间格式与字段名覆盖生效
+func TestBuildEncoderTimeFormatAndKeyNames(t *testing.T) {
+	entryTime := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: entryTime, Message: "key check"}
+	encoder := buildEncoder(Config{
+		Format:     FormatJSON,
+		TimeFormat: "rfc3339nano",
+		KeyNames:   KeyNames{Time: "@timestamp", Message: "message"},
+	})
+	buf, err := encoder.EncodeEntry(entry, nil)
+	require.NoError(t, err)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, entryTime.Format(time.RFC3339Nano), record["@timestamp"])
+	require.Equal(t, "key check", record["message"])
+	require.NotContains(t, record, "time")
+	require.NotContains(t, record, "msg")
+	require.Contains(t, record, "level")
+
+	defaultBuf, err := buildEncoder(Config{Format: FormatJSON}).EncodeEntry(entry, nil)
+	require.NoError(t, err)
+	var defaultRecord map[string]any
+	require.NoError(t, json.Unmarshal(defaultBuf.Bytes(), &defaultRecord))
+	require.Equal(t, "2024-05-06T07:08:09.123Z", defaultRecord["time"])
+	require.Equal(t, "key check", defaultRecord["msg"])
+}
+
+// TestNewZapLoggerBuffered 验证缓冲写入在 Sync 后全部落盘
+func TestNewZapLoggerBuffered(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "buffered.log")
+	zapLogger, err := NewZapLogger(Config{
+		Level:      LevelInfo,
+		Format:     FormatJSON,
+		OutputPath: logPath,
+		Buffer:     BufferConfig{Enabled: true, Size: 1 << 20, FlushInterval: time.Hour},
+	})
+	require.NoError(t, err)
+
+	const entries = 1000
+	for i := 0; i < entries; i++ {
+		zapLogger.Info("buffered entry", zap.Int("seq", i))
+	}
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Less(t, strings.Count(string(logData), "buffered entry"), entries, "entries should stay buffered before Sync")
+
+	require.NoError(t, zapLogger.Sync())
+	logData, err = os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Equal(t, entries, strings.Count(string(logData), "buffered entry"))
+}
+
+// TestNewZapLoggerOutputs 验证按级别拆分输出时 warn 写入文件而 info 仅输出到 stdout
+func TestNewZapLoggerOutputs(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "warn.log")
+	zapLogger, err := NewZapLogger(Config{
+		Level:  LevelDebug,
+		Format: FormatJSON,
+		Outputs: []OutputConfig{
+			{MaxLevel: LevelInfo},
+			{MinLevel: LevelWarn, OutputPath: logPath},
+		},
+	})
+	require.NoError(t, err)
+
+	zapLogger.Info("info entry")
+	zapLogger.Warn("warn entry")
+	zapLogger.Error("error entry")
+	require.NoError(t, zapLogger.Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(logData), "warn entry")
+	require.Contains(t, string(logData), "error entry")
+	require.NotContains(t, string(logData), "info entry")
+}
+
+// TestCallerPointsAtCallSite 验证包级函数、ZapLogger 方法及其 Context 变体的 caller 均指向调用方代码行
+func TestCallerPointsAtCallSite(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "caller_site.log")
+	zapLogger, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath})
+	require.NoError(t, err)
+	SetLogger(zapLogger)
+	ctx := context.Background()
+
+	expected := make([]string, 0, 4)
+	logAt := func(log func()) {
+		_, file, line, ok := runtime.Caller(1)
+		require.True(t, ok)
+		expected = append(expected, fmt.Sprintf("%s:%d", filepath.Base(file), line))
+		log()
+	}
+	logAt(func() { Info("package info") })
+	logAt(func() { ContextInfo(ctx, "package context info") })
+	logAt(func() { zapLogger.Info("method info") })
+	logAt(func() { zapLogger.ContextInfo(ctx, "method context info") })
+	require.NoError(t, Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, len(expected))
+	for i, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Equal(t, "logger/"+expected[i], record["caller"], record["msg"])
+	}
+}
+
+// TestNewWithObserverCore 验证注入 observer core 后日志写入内存，无需读写文件或修改默认实例
+func TestNewWithObserverCore(t *testing.T) {
+	core, observed := observer.New(zapcore.InfoLevel)
+	zapLogger, err := New(Config{}, core)
+	require.NoError(t, err)
+
+	ctx := WithTraceID(context.Background(), "trace-observer")
+	zapLogger.Debug("dropped entry")
+	zapLogger.Info("plain entry", zap.String("user", "alice"))
+	zapLogger.ContextWarn(ctx, "context entry")
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 2)
+	require.Equal(t, "plain entry", entries[0].Message)
+	require.Equal(t, "alice", entries[0].ContextMap()["user"])
+	require.Contains(t, entries[0].Caller.File, "logger_test.go")
+	require.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	require.Equal(t, "trace-observer", entries[1].ContextMap()["trace_id"])
+}
+
+// TestCompactErrorsAndStacktrace 验证紧凑错误编码输出数值型 error_code 且不含 errorVerbose，DisableStacktrace 关闭调用栈
+func TestCompactErrorsAndStacktrace(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "compact.log")
+	zapLogger, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath, CompactErrors: true})
+	require.NoError(t, err)
+	bizErr := fmt.Errorf("load order: %w", errs.NotFound("订单不存在", errors.New("record not found")))
+	zapLogger.Error("query failed", zap.Error(bizErr))
+	zapLogger.WithFields(zap.Error(errors.New("plain failure"))).Warn("with error field")
+	require.NoError(t, zapLogger.Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 2)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	require.Equal(t, "load order: 订单不存在", record["error"])
+	require.Equal(t, float64(errs.ERR_CODE_NOT_FOUND), record["error_code"])
+	require.NotContains(t, record, "errorVerbose")
+	require.Contains(t, record, "stack", "error logs should carry stacktrace by default")
+	var plainRecord map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &plainRecord))
+	require.Equal(t, "plain failure", plainRecord["error"])
+	require.NotContains(t, plainRecord, "error_code")
+
+	noStackPath := filepath.Join(t.TempDir(), "nostack.log")
+	noStackLogger, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON, OutputPath: noStackPath, DisableStacktrace: true})
+	require.NoError(t, err)
+	noStackLogger.Error("no stack")
+	require.NoError(t, noStackLogger.Sync())
+	logData, err = os.ReadFile(noStackPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(logData), `"stack"`)
+}
+
+// TestDisableStdoutWhenFile 验证设置 OutputPath 且关闭 stdout 双写时日志仅写入文件，默认仍同时写入 stdout
+func TestDisableStdoutWhenFile(t *testing.T) {
+	stdoutPath := filepath.Join(t.TempDir(), "stdout.log")
+	stdoutFile, err := os.Create(stdoutPath)
+	require.NoError(t, err)
+	defer stdoutFile.Close()
+	originalStdout := os.Stdout
+	os.Stdout = stdoutFile
+	defer func() { os.Stdout = originalStdout }()
+
+	logPath := filepath.Join(t.TempDir(), "file_only.log")
+	fileOnly, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath, DisableStdoutWhenFile: true})
+	require.NoError(t, err)
+	fileOnly.Info("file only")
+	require.NoError(t, fileOnly.Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Contains(t, string(logData), "file only")
+	stdoutData, err := os.ReadFile(stdoutPath)
+	require.NoError(t, err)
+	require.Empty(t, stdoutData, "nothing should be written to stdout")
+
+	teePath := filepath.Join(t.TempDir(), "tee.log")
+	tee, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON, OutputPath: teePath})
+	require.NoError(t, err)
+	tee.Info("tee")
+	require.NoError(t, tee.Sync())
+	stdoutData, err = os.ReadFile(stdoutPath)
+	require.NoError(t, err)
+	require.Contains(t, string(stdoutData), "tee", "default config should still copy to stdout")
+}
+
+// TestReconfigure 验证运行时从 JSON 切换到 console 格式，旧实例的缓冲内容在替换前刷新，之后的日志使用新编码器
+func TestReconfigure(t *testing.T) {
+	activeMu.RLock()
+	previous := activeLogger
+	activeMu.RUnlock()
+	defer SetLogger(previous)
+	defer func(delay time.Duration) { reconfigureCloseDelay = delay }(reconfigureCloseDelay)
+	reconfigureCloseDelay = 200 * time.Millisecond
+
+	logPath := filepath.Join(t.TempDir(), "reconfigure.log")
+	jsonLogger, err := NewZapLogger(Config{
+		Level:                 LevelInfo,
+		Format:                FormatJSON,
+		OutputPath:            logPath,
+		DisableStdoutWhenFile: true,
+		Buffer:                BufferConfig{Enabled: true, Size: 1 << 20, FlushInterval: time.Hour},
+	})
+	require.NoError(t, err)
+	SetLogger(jsonLogger)
+	L().Info("before reconfigure")
+
+	require.NoError(t, Reconfigure(Config{
+		Level:                 LevelInfo,
+		Format:                FormatConsole,
+		OutputPath:            logPath,
+		DisableStdoutWhenFile: true,
+		DisableColor:          true,
+	}))
+	// 替换前取得的旧实例在延迟关闭前仍可写入
+	jsonLogger.Info("in-flight write")
+	require.NoError(t, jsonLogger.Sync())
+	L().Info("after reconfigure")
+	require.NoError(t, Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, json.Valid([]byte(lines[0])), "entry before reconfigure should be JSON: %s", lines[0])
+	require.Contains(t, lines[0], "before reconfigure")
+	require.True(t, json.Valid([]byte(lines[1])), "in-flight entry should use the previous encoder: %s", lines[1])
+	require.Contains(t, lines[1], "in-flight write")
+	require.False(t, json.Valid([]byte(lines[2])), "entry after reconfigure should use console encoder: %s", lines[2])
+	require.Contains(t, lines[2], "after reconfigure")
+
+	require.Eventually(t, func() bool {
+		return jsonLogger.Sync() != nil
+	}, 2*time.Second, 20*time.Millisecond, "previous logger should be closed after the delay")
+}
+
+// TestNamedAndWithFields 验证子 logger 携带名称与固定字段，且 caller 指向调用方
+func TestNamedAndWithFields(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "named.log")
+	SetLogger(NewLogger(&Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath}))
+
+	Named("db").Info("named entry")
+	WithFields(zap.String("component", "http")).Info("fields entry")
+	require.NoError(t, Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 2)
+
+	var named, withFields map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &named))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &withFields))
+	require.Equal(t, "db", named["logger"])
+	require.Equal(t, "http", withFields["component"])
+	require.Contains(t, named["caller"], "logger_test.go")
+	require.Contains(t, withFields["caller"], "logger_test.go")
+}
+
+// TestIntoContextFromContext 验证上下文 logger 携带的字段出现在下游日志中，未设置时回退到默认实例
+func TestIntoContextFromContext(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "context.log")
+	SetLogger(NewLogger(&Config{Level: LevelInfo, Format: FormatJSON, OutputPath: logPath}))
+
+	ctx := IntoContext(context.Background(), WithFields(zap.String("request_id", "req-1")))
+	handleDownstream := func(ctx context.Context) {
+		FromContext(ctx).Info("downstream entry")
+	}
+	handleDownstream(ctx)
+	FromContext(context.Background()).Info("fallback entry")
+	require.NoError(t, Sync())
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 2)
+
+	var downstream, fallback map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &downstream))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &fallback))
+	require.Equal(t, "req-1", downstream["request_id"])
+	require.Equal(t, "fallback entry", fallback["msg"])
+	require.NotContains(t, fallback, "request_id")
+}
+
 // assertLogContains 校验日志文件包含指定内容
 func assertLogContains(t *testing.T, logPath string, content string) {
 	t.Helper()
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereal3x/apc/cache"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultRedisCoreWriteTimeout = time.Second
+
+// RedisCore 将日志以 JSON 行写入 Redis 列表的 zapcore.Core，列表按 maxLen 截断，用于轻量的应用内日志查看
+type RedisCore struct {
+	zapcore.LevelEnabler
+	client  *cache.RedisClient
+	key     string
+	maxLen  int64
+	encoder zapcore.Encoder
+}
+
+// NewRedisCore 创建写入 Redis 列表的日志 core，最新日志位于列表头部，maxLen <= 0 时不截断
+func NewRedisCore(client *cache.RedisClient, key string, maxLen int64, level zapcore.LevelEnabler) *RedisCore {
+	return &RedisCore{
+		LevelEnabler: level,
+		client:       client,
+		key:          key,
+		maxLen:       maxLen,
+		encoder:      buildEncoder(Config{Format: FormatJSON}),
+	}
+}
+
+// With 返回附带固定字段的 core
+func (core *RedisCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *core
+	clone.encoder = core.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return &clone
+}
+
+// Check 级别满足时将当前 core 加入待写入列表
+func (core *RedisCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core.Enabled(entry.Level) {
+		return checked.AddCore(entry, core)
+	}
+	return checked
+}
+
+// Write 编码日志并通过单次管道执行 LPUSH 与 LTRIM
+func (core *RedisCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := core.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return fmt.Errorf("encode redis log entry: %w", err)
+	}
+	line := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRedisCoreWriteTimeout)
+	defer cancel()
+	pipe := core.client.Pipeline()
+	pipe.LPush(ctx, core.key, line)
+	if core.maxLen > 0 {
+		pipe.LTrim(ctx, core.key, 0, core.maxLen-1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("write redis log %q: %w", core.key, err)
+	}
+	return nil
+}
+
+// Sync 每条日志均已同步写入 Redis，无需额外刷新
+func (core *RedisCore) Sync() error {
+	return nil
+}
+
+// Tee 返回同时写入当前输出与额外 core 的 logger，例如搭配 RedisCore 将错误日志同步到 Redis
+func (zapLogger *ZapLogger) Tee(cores ...zapcore.Core) *ZapLogger {
+	return &ZapLogger{logger: zapLogger.logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(append([]zapcore.Core{core}, cores...)...)
+	}))}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereal3x/apc/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestRedisCoreTee 验证 error 日志同时写入 stdout 与 Redis 列表，低级别日志不进入列表
+func TestRedisCoreTee(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("redis not available: %v", err)
+	}
+	const listKey = "test:logger:redis_core"
+	defer client.Del(context.Background(), listKey)
+	client.Del(context.Background(), listKey)
+
+	redisClient := cache.NewRedisClient(client)
+	zapLogger, err := NewZapLogger(Config{Level: LevelInfo, Format: FormatJSON})
+	require.NoError(t, err)
+	teeLogger := zapLogger.Tee(NewRedisCore(redisClient, listKey, 3, zapcore.ErrorLevel))
+
+	teeLogger.Info("info entry")
+	for i := 0; i < 3; i++ {
+		teeLogger.Error("redis entry", zap.Int("seq", i))
+	}
+	require.NoError(t, teeLogger.Sync())
+
+	lines, err := redisClient.LRange(context.Background(), listKey, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Equal(t, "redis entry", record["msg"])
+		require.Equal(t, "ERROR", record["level"])
+		require.EqualValues(t, 2-i, record["seq"])
+	}
+
+	teeLogger.Error("redis entry", zap.Int("seq", 3))
+	length, err := redisClient.LLen(context.Background(), listKey)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), length, "list should be trimmed to maxLen")
+}
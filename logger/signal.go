@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// exitOnSignal 刷新日志后结束进程，退出码遵循 128+信号值 约定，测试中可替换
+var exitOnSignal = func(sig os.Signal) {
+	if sysSig, ok := sig.(syscall.Signal); ok {
+		os.Exit(128 + int(sysSig))
+	}
+	os.Exit(1)
+}
+
+// RegisterSignalFlush 注册终止信号处理，收到信号时同步默认日志实例（含缓冲写入）后退出进程
+// 未指定信号时监听 SIGINT/SIGTERM，返回的 stop 函数用于注销处理，可重复调用
+func RegisterSignalFlush(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	sigCh := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigCh, signals...)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			signal.Stop(sigCh)
+			_ = Sync()
+			exitOnSignal(sig)
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// syncCountingLogger 统计 Sync 调用次数的测试 logger
+type syncCountingLogger struct {
+	Logger
+	syncs atomic.Int32
+}
+
+// Sync 记录一次同步调用
+func (countingLogger *syncCountingLogger) Sync() error {
+	countingLogger.syncs.Add(1)
+	return nil
+}
+
+// TestRegisterSignalFlush 验证收到信号时调用 Sync 并退出，stop 后不再处理
+func TestRegisterSignalFlush(t *testing.T) {
+	countingLogger := &syncCountingLogger{Logger: &ZapLogger{logger: zap.NewNop()}}
+	previous := activeLogger
+	SetLogger(countingLogger)
+	defer SetLogger(previous)
+
+	exited := make(chan os.Signal, 1)
+	previousExit := exitOnSignal
+	exitOnSignal = func(sig os.Signal) { exited <- sig }
+	defer func() { exitOnSignal = previousExit }()
+
+	stop := RegisterSignalFlush(syscall.SIGHUP)
+	defer stop()
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(syscall.SIGHUP))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	select {
+	case sig := <-exited:
+		require.Equal(t, syscall.SIGHUP, sig)
+	case <-ctx.Done():
+		t.Fatal("signal handler not invoked")
+	}
+	require.Equal(t, int32(1), countingLogger.syncs.Load())
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/ethereal3x/apc/errs"
 	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
@@ -16,12 +23,25 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
-const defaultOTLPTraceURLPath = "/v1/traces"
+const (
+	defaultOTLPTraceURLPath = "/v1/traces"
+	defaultShutdownTimeout  = 5 * time.Second
+)
 
 var tracerName = "default_tracer"
 
+// activeShutdown 最近一次 InitProvider 返回的关闭函数
+var activeShutdown = func(context.Context) error { return nil }
+
+// initialized 是否已通过 InitProvider（配置了 collector）或 InitNoop 安装 tracer provider
+var initialized atomic.Bool
+
+// ErrNotInitialized 尚未安装 tracer provider
+var ErrNotInitialized = errors.New("tracing: tracer provider not initialized")
+
 // Config 定义 tracing 初始化配置
 type Config struct {
 	ServiceName string         `yaml:"service_name" json:"service_name"`
@@ -61,8 +81,37 @@ type otlpTraceHTTPSettings struct {
 	headers    map[string]string
 }
 
+// Option 定义 tracing provider 初始化可选项
+type Option func(*providerOptions)
+
+// providerOptions 汇总 tracing provider 初始化可选项
+type providerOptions struct {
+	batchOptions []sdktrace.BatchSpanProcessorOption
+	syncExport   bool
+	propagators  []propagation.TextMapPropagator
+}
+
+// WithBatchOptions 透传批量 span 处理器参数，如 MaxQueueSize、BatchTimeout、MaxExportBatchSize
+func WithBatchOptions(batchOptions ...sdktrace.BatchSpanProcessorOption) Option {
+	return func(options *providerOptions) {
+		options.batchOptions = append(options.batchOptions, batchOptions...)
+	}
+}
+
+// WithSyncExport 使用同步处理器在 span 结束时立即导出，适合测试与低延迟开发环境，生产环境慎用
+func WithSyncExport() Option {
+	return func(options *providerOptions) { options.syncExport = true }
+}
+
+// WithPropagators 指定跨服务传播格式，未指定时默认使用 TraceContext + Baggage + B3 多头组合
+func WithPropagators(propagators ...propagation.TextMapPropagator) Option {
+	return func(options *providerOptions) {
+		options.propagators = append(options.propagators, propagators...)
+	}
+}
+
 // InitProvider 根据配置初始化 tracing provider
-func InitProvider(cfg Config) (func(ctx context.Context) error, error) {
+func InitProvider(cfg Config, opts ...Option) (func(ctx context.Context) error, error) {
 	if cfg.Reporter.CollectorEndpoint == "" {
 		return func(context.Context) error { return nil }, nil
 	}
@@ -74,21 +123,95 @@ func InitProvider(cfg Config) (func(ctx context.Context) error, error) {
 	if err != nil {
 		return nil, fmt.Errorf("new otlp trace http exporter: %w", err)
 	}
-	provider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithSampler(buildSampler(cfg.Sampler)),
+	options := providerOptions{}
+	for _, option := range opts {
+		option(&options)
+	}
+	provider := newTracerProvider(exporter, serviceName, cfg.Sampler, options)
+	otel.SetTracerProvider(provider)
+	tracerName = serviceName
+	otel.SetTextMapPropagator(buildPropagator(options))
+	shutdown := func(ctx context.Context) error {
+		return shutdownWithTimeout(ctx, provider.Shutdown, defaultShutdownTimeout)
+	}
+	activeShutdown = shutdown
+	initialized.Store(true)
+	return shutdown, nil
+}
+
+// InitNoop 安装 no-op tracer provider，Start/TraceID/RecordError 均可安全调用且几乎无开销
+// 适用于单元测试和未部署 collector 的环境，返回的关闭函数为空操作
+// 未调用任何 Init 时包内默认同样使用 no-op provider，库代码可无条件调用 Start
+func InitNoop() func(ctx context.Context) error {
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	shutdown := func(context.Context) error { return nil }
+	activeShutdown = shutdown
+	initialized.Store(true)
+	return shutdown
+}
+
+// Initialized 返回是否已安装 tracer provider，collector_endpoint 为空时 InitProvider 不安装，返回 false
+func Initialized() bool {
+	return initialized.Load()
+}
+
+// HealthCheck 健康检查函数，未安装 tracer provider 时返回 ErrNotInitialized
+func HealthCheck(context.Context) error {
+	if !Initialized() {
+		return ErrNotInitialized
+	}
+	return nil
+}
+
+// newTracerProvider 使用指定 exporter 与可选项构造 tracer provider
+func newTracerProvider(exporter sdktrace.SpanExporter, serviceName string, sampler SamplerConfig, options providerOptions) *sdktrace.TracerProvider {
+	processor := sdktrace.WithBatcher(exporter, options.batchOptions...)
+	if options.syncExport {
+		processor = sdktrace.WithSyncer(exporter)
+	}
+	return sdktrace.NewTracerProvider(
+		processor,
+		sdktrace.WithSampler(buildSampler(sampler)),
 		sdktrace.WithResource(resource.NewSchemaless(
 			semconv.ServiceNameKey.String(serviceName),
 		)),
 	)
-	otel.SetTracerProvider(provider)
-	tracerName = serviceName
+}
+
+// ShutdownWithTimeout 在 d 内关闭当前 tracing provider 并刷新剩余 span，超时后放弃等待并返回错误
+func ShutdownWithTimeout(d time.Duration) error {
+	return shutdownWithTimeout(context.Background(), activeShutdown, d)
+}
+
+// shutdownWithTimeout 以有界时长执行 shutdown，即使 exporter 忽略 context 也保证按时返回
+func shutdownWithTimeout(ctx context.Context, shutdown func(context.Context) error, d time.Duration) error {
+	shutdownCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- shutdown(shutdownCtx)
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("shutdown tracer provider: %w", err)
+		}
+		return nil
+	case <-shutdownCtx.Done():
+		log.Printf("tracing: shutdown tracer provider timed out, pending spans may be dropped: %v", shutdownCtx.Err())
+		return fmt.Errorf("shutdown tracer provider: %w", shutdownCtx.Err())
+	}
+}
+
+// buildPropagator 根据可选项构造全局传播器
+func buildPropagator(options providerOptions) propagation.TextMapPropagator {
+	if len(options.propagators) > 0 {
+		return propagation.NewCompositeTextMapPropagator(options.propagators...)
+	}
 	b3Propagator := b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader))
-	propagator := propagation.NewCompositeTextMapPropagator(
+	return propagation.NewCompositeTextMapPropagator(
 		propagation.TraceContext{}, propagation.Baggage{}, b3Propagator,
 	)
-	otel.SetTextMapPropagator(propagator)
-	return provider.Shutdown, nil
 }
 
 // newOTLPTraceHTTPExporter 创建 OTLP HTTP trace exporter
@@ -177,26 +300,185 @@ func buildOTLPTraceHTTPOptions(cfg ReporterConfig) ([]otlptracehttp.Option, erro
 	return options, nil
 }
 
-// buildSampler 根据配置创建 tracing 采样器
+// buildSampler 根据配置创建 tracing 采样器，并包装 ForceSampleable 以支持 StartForced
 func buildSampler(cfg SamplerConfig) sdktrace.Sampler {
 	switch cfg.Type {
 	case "const":
 		if cfg.Param <= 0 {
-			return sdktrace.NeverSample()
+			return ForceSampleable(sdktrace.NeverSample())
 		}
-		return sdktrace.AlwaysSample()
+		return ForceSampleable(sdktrace.AlwaysSample())
 	case "ratio":
-		return sdktrace.TraceIDRatioBased(cfg.Param)
+		return ForceSampleable(sdktrace.TraceIDRatioBased(cfg.Param))
 	default:
-		return sdktrace.AlwaysSample()
+		return ForceSampleable(sdktrace.AlwaysSample())
+	}
+}
+
+const (
+	// forceSampleKey StartForced 写入的 span 属性，采样器据此强制采样
+	forceSampleKey = attribute.Key("sampling.forced")
+	// forceTraceStateKey 强制采样标记在 tracestate 中的键，随上下文传播给子 span 与下游服务
+	forceTraceStateKey   = "apc"
+	forceTraceStateValue = "force"
+)
+
+// forceSampler 识别强制采样标记的采样器，未标记时交给 base 决策
+type forceSampler struct {
+	base sdktrace.Sampler
+}
+
+// ForceSampleable 包装采样器使其识别 StartForced 的强制采样标记，InitProvider 已默认包装，
+// 自行构造 TracerProvider 时需用它包装采样器，否则 StartForced 退化为普通 Start
+func ForceSampleable(base sdktrace.Sampler) sdktrace.Sampler {
+	return forceSampler{base: base}
+}
+
+// ShouldSample 带有强制采样属性或父 span 已被强制采样时返回 RecordAndSample，并在 tracestate 写入标记
+func (sampler forceSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	traceState := trace.SpanContextFromContext(params.ParentContext).TraceState()
+	forced := traceState.Get(forceTraceStateKey) == forceTraceStateValue
+	for _, attr := range params.Attributes {
+		if attr.Key == forceSampleKey && attr.Value.AsBool() {
+			forced = true
+		}
+	}
+	if !forced {
+		return sampler.base.ShouldSample(params)
+	}
+	if marked, err := traceState.Insert(forceTraceStateKey, forceTraceStateValue); err == nil {
+		traceState = marked
 	}
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: traceState}
 }
 
-// Start 启动一个 span，始终从当前全局 tracer provider 获取 tracer
+// Description 返回采样器描述
+func (sampler forceSampler) Description() string {
+	return "ForceSampleable{" + sampler.base.Description() + "}"
+}
+
+// Start 启动一个 span，始终从当前 tracer provider 获取 tracer，初始化之前为 no-op
 func Start(ctx context.Context, name string) (context.Context, trace.Span) {
 	return otel.Tracer(tracerName).Start(ctx, name)
 }
 
+// StartForced 启动一个无视全局采样比例、必定被采样的 span，适用于结账等必须留痕的关键操作
+// OTel 的采样在 span 创建时由 provider 的采样器决定，无法事后改变：StartForced 通过 sampling.forced 属性提示
+// ForceSampleable 包装的采样器强制采样，并在 tracestate 中写入标记，使其子 span 及下游服务同样被采样
+func StartForced(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(forceSampleKey.Bool(true)))
+}
+
+// SpanBuilder 链式构造 span 的辅助类型，通过 Begin 获取，Start 时一次性应用属性与类型
+type SpanBuilder struct {
+	ctx   context.Context
+	name  string
+	attrs []attribute.KeyValue
+	kind  trace.SpanKind
+}
+
+// Begin 开始构造名为 name 的 span，需调用 Start 才会真正创建
+func Begin(ctx context.Context, name string) *SpanBuilder {
+	return &SpanBuilder{ctx: ctx, name: name}
+}
+
+// Attr 添加 span 属性，支持 string/bool/int/int64/float64 及其切片，其他类型按 fmt.Sprint 转为字符串
+func (builder *SpanBuilder) Attr(key string, value any) *SpanBuilder {
+	builder.attrs = append(builder.attrs, toAttribute(key, value))
+	return builder
+}
+
+// Kind 设置 span 类型，如 trace.SpanKindServer、trace.SpanKindProducer
+func (builder *SpanBuilder) Kind(kind trace.SpanKind) *SpanBuilder {
+	builder.kind = kind
+	return builder
+}
+
+// Start 使用全局 tracer 创建 span
+func (builder *SpanBuilder) Start() (context.Context, trace.Span) {
+	options := []trace.SpanStartOption{trace.WithAttributes(builder.attrs...)}
+	if builder.kind != trace.SpanKindUnspecified {
+		options = append(options, trace.WithSpanKind(builder.kind))
+	}
+	return otel.Tracer(tracerName).Start(builder.ctx, builder.name, options...)
+}
+
+// toAttribute 将任意值转换为 span 属性
+func toAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+// Tracer 返回指定 instrumentation scope 名称的 tracer，供各子系统使用独立的埋点作用域
+// 返回的 tracer 来自 otel 全局 provider，初始化之前为 no-op，初始化后自动切换到新安装的 provider，可在包级变量中缓存
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// StartNamed 使用指定名称的 tracer 启动一个 span
+func StartNamed(ctx context.Context, tracerName, spanName string) (context.Context, trace.Span) {
+	return Tracer(tracerName).Start(ctx, spanName)
+}
+
+// StartWithLinks 启动一个关联多个来源 span 的 span，适用于批量消费等无严格父子关系的场景
+func StartWithLinks(ctx context.Context, name string, links ...trace.Link) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithLinks(links...))
+}
+
+// SetBaggage 向 context 写入 baggage 键值，key 或 value 不合法时返回原 context
+func SetBaggage(ctx context.Context, key, value string) context.Context {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// GetBaggage 读取 context 中的 baggage 值，不存在时返回空字符串
+func GetBaggage(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// MapCarrier 基于 map[string]string 的传播载体，可直接承载 Kafka/NATS 等消息头
+type MapCarrier = propagation.MapCarrier
+
+// Inject 使用全局传播器将 ctx 中的链路信息写入任意载体，适用于消息队列等非 HTTP/gRPC 传输
+// 全局传播器由 InitProvider 设置，未初始化时不写入任何字段
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// Extract 使用全局传播器从载体中解析链路信息并返回携带远端 span 上下文的 ctx，后续 Start 将以其为父 span
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
 // TraceID 获取 TraceID
 func TraceID(ctx context.Context) string {
 	spanCtx := trace.SpanContextFromContext(ctx)
@@ -210,6 +492,9 @@ func SpanID(ctx context.Context) string {
 }
 
 // RecordError 会将 error 记录到当前 Span，并设置 Span 状态为 Error
+// 错误链中的 BizError 严重程度为 info（如 4xx 客户端错误、缓存未命中）时只记录错误事件，不修改 Span 状态，
+// 避免预期内的客户端错误污染错误看板；服务端错误与普通 error 仍设置为 Error
+// 未被采样的 span 不写入错误事件与状态，避免高并发路径上的无效开销
 func RecordError(ctx context.Context, err error) {
 	if err == nil {
 		return
@@ -218,6 +503,41 @@ func RecordError(ctx context.Context, err error) {
 	if span == nil {
 		return
 	}
-	span.RecordError(err)
-	span.SetStatus(codes.Error, err.Error())
+	if span.IsRecording() {
+		span.RecordError(err)
+		if !isClientError(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	if holder := errorLogger.Load(); holder != nil {
+		holder.logger.LogSpanError(ctx, err)
+	}
+}
+
+// isClientError 判断错误是否为预期内的客户端错误，依据错误链中 BizError 的严重程度
+func isClientError(err error) bool {
+	bizErr, ok := errs.AsBizError(err)
+	return ok && bizErr.Severity() == errs.SeverityInfo
+}
+
+// ErrorLogger 接收 RecordError 记录的错误，由日志组件实现以输出携带 trace_id 的错误日志
+// tracing 只依赖该接口，避免与 logger 包形成循环依赖
+type ErrorLogger interface {
+	LogSpanError(ctx context.Context, err error)
+}
+
+// errorLoggerHolder 包装 ErrorLogger 以便原子替换
+type errorLoggerHolder struct {
+	logger ErrorLogger
+}
+
+var errorLogger atomic.Pointer[errorLoggerHolder]
+
+// SetErrorLogger 设置 RecordError 的日志联动实现，传入 nil 关闭联动
+func SetErrorLogger(logger ErrorLogger) {
+	if logger == nil {
+		errorLogger.Store(nil)
+		return
+	}
+	errorLogger.Store(&errorLoggerHolder{logger: logger})
 }
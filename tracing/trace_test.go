@@ -3,12 +3,89 @@ package tracing
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/ethereal3x/apc/errs"
+	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// TestStartBeforeInit 需位于文件首位，在任何 provider 安装之前运行
+func TestStartBeforeInit(t *testing.T) {
+	cached := Tracer("before-init")
+	ctx, span := Start(context.Background(), "before-init")
+	if span == nil {
+		t.Fatal("Start 应返回非 nil span")
+	}
+	if span.IsRecording() {
+		t.Error("未初始化时 span 不应记录")
+	}
+	RecordError(ctx, errors.New("ignored"))
+	span.End()
+
+	// 初始化前缓存的 tracer 在安装 provider 后自动切换
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	_, span = cached.Start(context.Background(), "after-init")
+	span.End()
+	if ended := recorder.Ended(); len(ended) != 1 || ended[0].Name() != "after-init" {
+		t.Errorf("初始化前缓存的 tracer 应在安装 provider 后记录 span, got %d spans", len(ended))
+	}
+}
+
+func TestInitNoop(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	shutdown := InitNoop()
+	ctx, span := Start(context.Background(), "noop")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("no-op 模式下 span 不应记录")
+	}
+	RecordError(ctx, errors.New("ignored"))
+	if TraceID(ctx) != (trace.TraceID{}).String() {
+		t.Errorf("no-op 模式下 TraceID = %s, want 全零", TraceID(ctx))
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	if err := ShutdownWithTimeout(time.Second); err != nil {
+		t.Errorf("ShutdownWithTimeout: %v", err)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	previousInitialized := initialized.Load()
+	defer func() {
+		otel.SetTracerProvider(previous)
+		initialized.Store(previousInitialized)
+	}()
+
+	initialized.Store(false)
+	if _, err := InitProvider(Config{}); err != nil {
+		t.Fatalf("InitProvider: %v", err)
+	}
+	if err := HealthCheck(context.Background()); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("未配置 collector 时 HealthCheck = %v, want ErrNotInitialized", err)
+	}
+	InitNoop()
+	if err := HealthCheck(context.Background()); err != nil {
+		t.Errorf("InitNoop 后 HealthCheck = %v, want nil", err)
+	}
+}
+
 func TestInitProviderEmptyEndpoint(t *testing.T) {
 	shutdown, err := InitProvider(Config{})
 	if err != nil {
@@ -292,3 +369,386 @@ func TestResolveReporterHeadersAuthPriority(t *testing.T) {
 		t.Errorf("Authorization = %q, want %q", headers["Authorization"], expected)
 	}
 }
+
+// useSpanRecorder 安装内存 span 记录器作为全局 provider，测试结束后恢复
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestStartWithLinks(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	ctx := context.Background()
+
+	_, producer1 := Start(ctx, "producer-1")
+	producer1.End()
+	_, producer2 := Start(ctx, "producer-2")
+	producer2.End()
+
+	_, consumer := StartWithLinks(ctx, "batch-consumer",
+		trace.Link{SpanContext: producer1.SpanContext()},
+		trace.Link{SpanContext: producer2.SpanContext()},
+	)
+	consumer.End()
+
+	var links []sdktrace.Link
+	for _, span := range recorder.Ended() {
+		if span.Name() == "batch-consumer" {
+			links = span.Links()
+		}
+	}
+	if len(links) != 2 {
+		t.Fatalf("links len = %d, want 2", len(links))
+	}
+	if !links[0].SpanContext.Equal(producer1.SpanContext()) {
+		t.Errorf("links[0] = %v, want %v", links[0].SpanContext, producer1.SpanContext())
+	}
+	if !links[1].SpanContext.Equal(producer2.SpanContext()) {
+		t.Errorf("links[1] = %v, want %v", links[1].SpanContext, producer2.SpanContext())
+	}
+}
+
+func TestStartNamedInstrumentationScope(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	ctx := context.Background()
+
+	_, dbSpan := StartNamed(ctx, "apc/db", "query")
+	dbSpan.End()
+	_, httpSpan := StartNamed(ctx, "apc/http", "request")
+	httpSpan.End()
+
+	scopes := make(map[string]string)
+	for _, span := range recorder.Ended() {
+		scopes[span.Name()] = span.InstrumentationScope().Name
+	}
+	if scopes["query"] != "apc/db" {
+		t.Errorf("query scope = %q, want %q", scopes["query"], "apc/db")
+	}
+	if scopes["request"] != "apc/http" {
+		t.Errorf("request scope = %q, want %q", scopes["request"], "apc/http")
+	}
+	if Tracer("apc/db") == nil {
+		t.Error("Tracer 不应返回 nil")
+	}
+}
+
+// countingSpan 统计错误写入次数的测试 span
+type countingSpan struct {
+	trace.Span
+	recording   bool
+	recordCalls int
+	statusCalls int
+}
+
+func (span *countingSpan) IsRecording() bool { return span.recording }
+
+func (span *countingSpan) RecordError(error, ...trace.EventOption) { span.recordCalls++ }
+
+func (span *countingSpan) SetStatus(codes.Code, string) { span.statusCalls++ }
+
+func TestRecordErrorSkipsUnsampledSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		recording bool
+		wantCalls int
+	}{
+		{name: "未采样 span 不写入错误", recording: false, wantCalls: 0},
+		{name: "采样 span 写入错误", recording: true, wantCalls: 1},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			span := &countingSpan{Span: noop.Span{}, recording: testCase.recording}
+			ctx := trace.ContextWithSpan(context.Background(), span)
+			RecordError(ctx, errors.New("boom"))
+			if span.recordCalls != testCase.wantCalls || span.statusCalls != testCase.wantCalls {
+				t.Errorf("recordCalls=%d statusCalls=%d, want %d", span.recordCalls, span.statusCalls, testCase.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRecordErrorSamplers(t *testing.T) {
+	for _, sampler := range []sdktrace.Sampler{sdktrace.NeverSample(), sdktrace.AlwaysSample()} {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+		ctx, span := provider.Tracer("sampler-test").Start(context.Background(), "op")
+		RecordError(ctx, errors.New("boom"))
+		span.End()
+		sampled := span.SpanContext().IsSampled()
+		ended := recorder.Ended()
+		if !sampled && len(ended) != 0 {
+			t.Errorf("%s: 未采样 span 不应被记录", sampler.Description())
+		}
+		if sampled {
+			if len(ended) != 1 || len(ended[0].Events()) != 1 || ended[0].Status().Code != codes.Error {
+				t.Errorf("%s: 采样 span 应包含错误事件与状态", sampler.Description())
+			}
+		}
+		_ = provider.Shutdown(context.Background())
+	}
+}
+
+// TestRecordErrorBizErrorStatus 验证客户端类 BizError 只记录错误事件不设置 Error 状态，服务端错误与普通 error 仍为 Error
+func TestRecordErrorBizErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "资源不存在", err: errs.NotFound("user not found", nil), wantStatus: codes.Unset},
+		{name: "包装的参数错误", err: fmt.Errorf("handle: %w", errs.InvalidArgument("", nil)), wantStatus: codes.Unset},
+		{name: "服务内部错误", err: errs.Internal("db down", nil), wantStatus: codes.Error},
+		{name: "普通 error", err: errors.New("boom"), wantStatus: codes.Error},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := useSpanRecorder(t)
+			ctx, span := Start(context.Background(), "op")
+			RecordError(ctx, testCase.err)
+			span.End()
+			ended := recorder.Ended()
+			if len(ended) != 1 || len(ended[0].Events()) != 1 {
+				t.Fatalf("span 应包含一个错误事件")
+			}
+			if got := ended[0].Status().Code; got != testCase.wantStatus {
+				t.Errorf("status=%v, want %v", got, testCase.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	ctx := SetBaggage(context.Background(), "tenant", "acme")
+	if got := GetBaggage(ctx, "tenant"); got != "acme" {
+		t.Errorf("GetBaggage = %q, want %q", got, "acme")
+	}
+	if got := GetBaggage(ctx, "missing"); got != "" {
+		t.Errorf("GetBaggage missing = %q, want empty", got)
+	}
+	invalid := SetBaggage(ctx, "", "v")
+	if invalid != ctx {
+		t.Error("非法 key 应返回原 context")
+	}
+}
+
+func TestNewTracerProviderSyncExport(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	options := providerOptions{}
+	WithSyncExport()(&options)
+	provider := newTracerProvider(exporter, "sync-service", SamplerConfig{}, options)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	_, span := provider.Tracer("sync-test").Start(context.Background(), "sync-span")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("同步导出后 spans len = %d, want 1", len(spans))
+	}
+	if spans[0].Name != "sync-span" {
+		t.Errorf("span name = %q, want %q", spans[0].Name, "sync-span")
+	}
+}
+
+func TestNewTracerProviderBatchOptions(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	options := providerOptions{}
+	WithBatchOptions(sdktrace.WithBatchTimeout(time.Hour), sdktrace.WithMaxExportBatchSize(10))(&options)
+	if len(options.batchOptions) != 2 {
+		t.Fatalf("batchOptions len = %d, want 2", len(options.batchOptions))
+	}
+	provider := newTracerProvider(exporter, "batch-service", SamplerConfig{}, options)
+
+	_, span := provider.Tracer("batch-test").Start(context.Background(), "batch-span")
+	span.End()
+	if got := len(exporter.GetSpans()); got != 0 {
+		t.Errorf("批量导出未到超时前 spans len = %d, want 0", got)
+	}
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := len(exporter.GetSpans()); got != 1 {
+		t.Errorf("ForceFlush 后 spans len = %d, want 1", got)
+	}
+	_ = provider.Shutdown(context.Background())
+}
+
+func TestBuildPropagatorB3Single(t *testing.T) {
+	options := providerOptions{}
+	WithPropagators(b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))(&options)
+	propagator := buildPropagator(options)
+
+	useSpanRecorder(t)
+	ctx, span := Start(context.Background(), "b3-single")
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if carrier.Get("b3") == "" {
+		t.Fatalf("B3 单头未注入: %v", carrier)
+	}
+	if carrier.Get("traceparent") != "" {
+		t.Errorf("仅配置 B3 时不应注入 traceparent: %v", carrier)
+	}
+
+	extracted := trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier))
+	if extracted.TraceID() != span.SpanContext().TraceID() {
+		t.Errorf("TraceID = %s, want %s", extracted.TraceID(), span.SpanContext().TraceID())
+	}
+	if extracted.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("SpanID = %s, want %s", extracted.SpanID(), span.SpanContext().SpanID())
+	}
+}
+
+func TestShutdownWithTimeoutUnreachableCollector(t *testing.T) {
+	_, err := InitProvider(Config{
+		ServiceName: "unreachable-service",
+		Reporter:    ReporterConfig{CollectorEndpoint: "http://10.255.255.1:4318/v1/traces"},
+	})
+	if err != nil {
+		t.Fatalf("InitProvider: %v", err)
+	}
+	_, span := Start(context.Background(), "pending-span")
+	span.End()
+
+	start := time.Now()
+	err = ShutdownWithTimeout(200 * time.Millisecond)
+	elapsed := time.Since(start)
+	// 导出失败由 otel 错误处理器上报，这里只要求 shutdown 按时返回
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want nil or DeadlineExceeded", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("shutdown 耗时 %v，超出超时上限", elapsed)
+	}
+}
+
+func TestShutdownWithTimeoutIgnoresBlockingShutdown(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	blocking := func(context.Context) error {
+		<-block
+		return nil
+	}
+	start := time.Now()
+	err := shutdownWithTimeout(context.Background(), blocking, 100*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown 耗时 %v，超出超时上限", elapsed)
+	}
+}
+
+func TestBuildPropagatorDefault(t *testing.T) {
+	fields := buildPropagator(providerOptions{}).Fields()
+	want := map[string]bool{"traceparent": false, "baggage": false, "x-b3-traceid": false}
+	for _, field := range fields {
+		if _, ok := want[field]; ok {
+			want[field] = true
+		}
+	}
+	for field, found := range want {
+		if !found {
+			t.Errorf("默认传播器缺少字段 %q, fields=%v", field, fields)
+		}
+	}
+}
+
+func TestInjectExtractMapCarrier(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(buildPropagator(providerOptions{}))
+	defer otel.SetTextMapPropagator(previous)
+	useSpanRecorder(t)
+
+	ctx, span := Start(context.Background(), "produce")
+	defer span.End()
+	ctx = SetBaggage(ctx, "tenant", "acme")
+	headers := map[string]string{}
+	Inject(ctx, MapCarrier(headers))
+	if headers["traceparent"] == "" {
+		t.Fatalf("消息头未注入 traceparent: %v", headers)
+	}
+
+	extracted := Extract(context.Background(), MapCarrier(headers))
+	if got := TraceID(extracted); got != span.SpanContext().TraceID().String() {
+		t.Errorf("TraceID = %s, want %s", got, span.SpanContext().TraceID())
+	}
+	if !trace.SpanContextFromContext(extracted).IsRemote() {
+		t.Error("解析出的 span 上下文应标记为远端")
+	}
+	if got := GetBaggage(extracted, "tenant"); got != "acme" {
+		t.Errorf("GetBaggage = %q, want %q", got, "acme")
+	}
+}
+
+func TestStartForcedUnderRatioZero(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(buildSampler(SamplerConfig{Type: "ratio", Param: 0})),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	}()
+
+	_, normal := Start(context.Background(), "browse")
+	if normal.IsRecording() {
+		t.Error("ratio 0 下普通 span 不应被采样")
+	}
+	normal.End()
+
+	ctx, forced := StartForced(context.Background(), "checkout")
+	if !forced.IsRecording() || !forced.SpanContext().IsSampled() {
+		t.Fatal("强制采样 span 应被记录并采样")
+	}
+	_, child := Start(ctx, "charge")
+	if !child.IsRecording() {
+		t.Error("强制采样 span 的子 span 应继承采样")
+	}
+	child.End()
+	forced.End()
+
+	if got := len(recorder.Ended()); got != 2 {
+		t.Errorf("ended spans = %d, want 2", got)
+	}
+}
+
+func TestSpanBuilder(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	ctx, span := Begin(context.Background(), "consume").
+		Attr("messaging.system", "kafka").
+		Attr("messaging.batch.size", 3).
+		Kind(trace.SpanKindConsumer).
+		Start()
+	if TraceID(ctx) != span.SpanContext().TraceID().String() {
+		t.Error("返回的 context 应携带新 span")
+	}
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(ended))
+	}
+	if ended[0].SpanKind() != trace.SpanKindConsumer {
+		t.Errorf("SpanKind = %v, want %v", ended[0].SpanKind(), trace.SpanKindConsumer)
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range ended[0].Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	if attrs["messaging.system"].AsString() != "kafka" || attrs["messaging.batch.size"].AsInt64() != 3 {
+		t.Errorf("unexpected attributes: %v", ended[0].Attributes())
+	}
+}